@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog 错误码目录文件
+//
+//	package: errs
+//	module: order
+//	locale: zh
+//	errors:
+//	  - name: OrderNotFound
+//	    code: 10001
+//	    httpCode: 404
+//	    severity: info
+//	    comment: 订单不存在
+//	    messages:
+//	      zh: 订单 {id} 不存在
+//	      en: Order {id} not found
+type Catalog struct {
+	Package string      `yaml:"package" toml:"package"` // 生成的包名，可以用 -pkg 指定
+	Module  string      `yaml:"module" toml:"module"`   // 默认模块
+	Locale  string      `yaml:"locale" toml:"locale"`   // 内置信息使用的语言，默认 zh
+	Errors  []ErrorItem `yaml:"errors" toml:"errors"`
+}
+
+// ErrorItem 一个错误码
+type ErrorItem struct {
+	Name      string            `yaml:"name" toml:"name"`           // 变量名
+	Module    string            `yaml:"module" toml:"module"`       // 所属模块，默认用 Catalog.Module
+	Code      int               `yaml:"code" toml:"code"`           // 业务错误码
+	HttpCode  int               `yaml:"httpCode" toml:"httpCode"`   // http状态码，可选
+	Severity  jerrno.Severity   `yaml:"severity" toml:"severity"`   // 严重程度，可选
+	Retryable *bool             `yaml:"retryable" toml:"retryable"` // 是否可以重试，可选
+	Comment   string            `yaml:"comment" toml:"comment"`     // 注释
+	Messages  map[string]string `yaml:"messages" toml:"messages"`   // 语言 => 信息
+}
+
+// LoadCatalog 加载目录文件，按扩展名识别 yaml、toml
+func LoadCatalog(name string) (*Catalog, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var c Catalog
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&c)
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), &c)
+		if err == nil && len(md.Undecoded()) > 0 {
+			err = fmt.Errorf("未知的字段: %v", md.Undecoded())
+		}
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &c, c.fix()
+}
+
+// fix 设置默认值并检查
+func (c *Catalog) fix() error {
+	if c.Locale == "" {
+		c.Locale = "zh"
+	}
+	c.Locale = jerrno.NormalizeLocale(c.Locale)
+	names := make(map[string]bool)
+	codes := make(map[int]string)
+	for i := range c.Errors {
+		e := &c.Errors[i]
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			return fmt.Errorf("第 %d 个错误的名称 %q 无效，需要是导出的标识符", i+1, e.Name)
+		}
+		if names[e.Name] {
+			return fmt.Errorf("名称 %s 重复", e.Name)
+		}
+		names[e.Name] = true
+		if n, ok := codes[e.Code]; ok {
+			return fmt.Errorf("错误码 %d 重复: %s、%s", e.Code, n, e.Name)
+		}
+		codes[e.Code] = e.Name
+		if e.Module == "" {
+			e.Module = c.Module
+		}
+		msgs := make(map[string]string, len(e.Messages))
+		for k, v := range e.Messages {
+			msgs[jerrno.NormalizeLocale(k)] = v
+		}
+		e.Messages = msgs
+		if e.Msg(c.Locale) == "" {
+			return fmt.Errorf("%s 缺少信息", e.Name)
+		}
+	}
+	sort.SliceStable(c.Errors, func(i, j int) bool {
+		return c.Errors[i].Code < c.Errors[j].Code
+	})
+	return nil
+}
+
+// Msg 指定语言的信息，没有时用排序后的第一个
+func (e *ErrorItem) Msg(locale string) string {
+	if s, ok := e.Messages[locale]; ok {
+		return s
+	}
+	for _, l := range e.Locales() {
+		return e.Messages[l]
+	}
+	return ""
+}
+
+// Locales 已有的语言，排序后
+func (e *ErrorItem) Locales() []string {
+	ss := make([]string, 0, len(e.Messages))
+	for k := range e.Messages {
+		ss = append(ss, k)
+	}
+	sort.Strings(ss)
+	return ss
+}
+
+// Locales 所有错误用到的语言，内置语言在最前
+func (c *Catalog) Locales() []string {
+	m := map[string]bool{c.Locale: true}
+	ss := []string{c.Locale}
+	var others []string
+	for _, e := range c.Errors {
+		for k := range e.Messages {
+			if !m[k] {
+				m[k] = true
+				others = append(others, k)
+			}
+		}
+	}
+	sort.Strings(others)
+	return append(ss, others...)
+}
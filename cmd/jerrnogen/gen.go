@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by jerrnogen. DO NOT EDIT.
+{{- if .Source}}
+// source: {{.Source}}
+{{- end}}
+
+package {{.Package}}
+
+import "github.com/xtulnx/jkit-go/jerrno"
+{{if .Modules}}
+var (
+{{- range .Modules}}
+	{{.Var}} = jerrno.Module({{printf "%q" .Name}})
+{{- end}}
+)
+{{end}}
+var (
+{{- range .Errors}}
+{{- if .Comment}}
+	// {{.Name}} {{.Comment}}
+{{- end}}
+	{{.Name}} = {{.Expr}}
+{{- end}}
+)
+{{if .Messages}}
+func init() {
+{{- range .Messages}}
+	jerrno.Messages.SetMessages({{printf "%q" .Locale}}, map[int]string{
+{{- range .Items}}
+		{{.Code}}: {{printf "%q" .Msg}},
+{{- end}}
+	})
+{{- end}}
+}
+{{end}}`))
+
+type genModule struct {
+	Name, Var string
+}
+
+type genError struct {
+	Name, Comment, Expr string
+}
+
+type genMessage struct {
+	Code int
+	Msg  string
+}
+
+type genLocale struct {
+	Locale string
+	Items  []genMessage
+}
+
+// GenerateGo 生成 go 代码
+func GenerateGo(w io.Writer, c *Catalog, pkg, source string) error {
+	data := struct {
+		Source, Package string
+		Modules         []genModule
+		Errors          []genError
+		Messages        []genLocale
+	}{Source: source, Package: pkg}
+
+	moduleVars := make(map[string]string)
+	for _, e := range c.Errors {
+		if _, ok := moduleVars[e.Module]; ok || e.Module == "" {
+			continue
+		}
+		v := "module" + camelName(e.Module)
+		moduleVars[e.Module] = v
+		data.Modules = append(data.Modules, genModule{Name: e.Module, Var: v})
+	}
+
+	for _, e := range c.Errors {
+		data.Errors = append(data.Errors, genError{Name: e.Name, Comment: e.Comment, Expr: errorExpr(e, moduleVars[e.Module], c.Locale)})
+	}
+
+	for _, l := range c.Locales()[1:] {
+		var items []genMessage
+		for _, e := range c.Errors {
+			if s, ok := e.Messages[l]; ok {
+				items = append(items, genMessage{Code: e.Code, Msg: s})
+			}
+		}
+		data.Messages = append(data.Messages, genLocale{Locale: l, Items: items})
+	}
+
+	var b bytes.Buffer
+	if err := goTemplate.Execute(&b, data); err != nil {
+		return err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("格式化生成的代码失败: %w\n%s", err, b.String())
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// errorExpr 创建错误的表达式，有严重程度等附加信息时，用模块的 Register 登记附加后的错误
+func errorExpr(e ErrorItem, moduleVar, locale string) string {
+	owner := "jerrno"
+	if moduleVar != "" {
+		owner = moduleVar
+	}
+	msg := strconv.Quote(e.Msg(locale))
+	var expr string
+	if e.HttpCode != 0 {
+		expr = fmt.Sprintf("%s.NewErrWithHttpCode(%d, %d, %s)", owner, e.HttpCode, e.Code, msg)
+	} else {
+		expr = fmt.Sprintf("%s.NewErrWithCode(%d, %s)", owner, e.Code, msg)
+	}
+	var ext string
+	if e.Severity != jerrno.SeverityUnset {
+		ext += ".WithSeverity(jerrno.Severity" + camelName(e.Severity.String()) + ")"
+	}
+	if e.Retryable != nil {
+		ext += ".WithRetryable(" + strconv.FormatBool(*e.Retryable) + ")"
+	}
+	if ext == "" {
+		return expr
+	}
+	if moduleVar == "" {
+		moduleVar = "jerrno.Module(jerrno.ModuleDefault)"
+	}
+	return moduleVar + ".Register(" + expr + ext + ")"
+}
+
+// camelName 转成驼峰，如 order-item => OrderItem
+func camelName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// GenerateMarkdown 生成 markdown 文档，按模块分节，每个语言一列
+func GenerateMarkdown(w io.Writer, c *Catalog) error {
+	locales := c.Locales()
+	var modules []string
+	groups := make(map[string][]ErrorItem)
+	for _, e := range c.Errors {
+		m := e.Module
+		if m == "" {
+			m = jerrno.ModuleDefault
+		}
+		if _, ok := groups[m]; !ok {
+			modules = append(modules, m)
+		}
+		groups[m] = append(groups[m], e)
+	}
+
+	var b strings.Builder
+	b.WriteString("# 错误码\n")
+	for _, m := range modules {
+		b.WriteString("\n## " + m + "\n\n")
+		b.WriteString("| 错误码 | HTTP 状态码 | 名称 | 严重程度 | 可重试 |")
+		for _, l := range locales {
+			b.WriteString(" " + l + " |")
+		}
+		b.WriteString("\n| ---: | ---: | --- | --- | --- |" + strings.Repeat(" --- |", len(locales)) + "\n")
+		for _, e := range groups[m] {
+			httpCode := "-"
+			if e.HttpCode != 0 {
+				httpCode = strconv.Itoa(e.HttpCode)
+			}
+			severity := e.Severity
+			if severity == jerrno.SeverityUnset {
+				severity = jerrno.DefaultSeverity(e.Code)
+			}
+			retryable := jerrno.DefaultRetryable(e.Code) || jerrno.DefaultRetryable(e.HttpCode)
+			if e.Retryable != nil {
+				retryable = *e.Retryable
+			}
+			retry := "否"
+			if retryable {
+				retry = "是"
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |", e.Code, httpCode, e.Name, severity, retry)
+			for _, l := range locales {
+				b.WriteString(" " + markdownEscaper.Replace(e.Messages[l]) + " |")
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	c, err := LoadCatalog("testdata/errors.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = GenerateGo(&b, c, c.Package, "errors.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	src := b.String()
+	for _, want := range []string{
+		"// Code generated by jerrnogen. DO NOT EDIT.",
+		"package errs",
+		`moduleOrder = jerrno.Module("order")`,
+		`modulePay   = jerrno.Module("pay")`,
+		"// OrderNotFound 订单不存在\n",
+		`OrderNotFound = moduleOrder.Register(moduleOrder.NewErrWithHttpCode(404, 10001, "订单 {id} 不存在").WithSeverity(jerrno.SeverityInfo))`,
+		`OrderPaid     = moduleOrder.NewErrWithCode(10002, "订单已支付")`,
+		`PayBusy       = modulePay.Register(modulePay.NewErrWithCode(20001, "支付繁忙，请稍候再试").WithRetryable(true))`,
+		`jerrno.Messages.SetMessages("en", map[int]string{` + "\n\t\t10001: \"Order {id} not found\",\n\t})",
+		`jerrno.Messages.SetMessages("en-us", map[int]string{`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("生成的代码缺少 %q:\n%s", want, src)
+		}
+	}
+
+	b.Reset()
+	if err = GenerateMarkdown(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, want := range []string{
+		"## order\n",
+		"| 错误码 | HTTP 状态码 | 名称 | 严重程度 | 可重试 | zh | en | en-us |",
+		"| 10001 | 404 | OrderNotFound | info | 否 | 订单 {id} 不存在 | Order {id} not found |  |",
+		"| 20001 | - | PayBusy | warn | 是 | 支付繁忙，请稍候再试 |  | Payment is busy, please retry later |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown 缺少 %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestLoadToml(t *testing.T) {
+	c, err := LoadCatalog("testdata/errors.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = GenerateGo(&b, c, c.Package, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := `UserDisabled = jerrno.Module(jerrno.ModuleDefault).Register(jerrno.NewErrWithCode(30001, "用户已禁用").WithSeverity(jerrno.SeverityWarn))`
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("生成的代码缺少 %q:\n%s", want, b.String())
+	}
+}
+
+func TestCatalogCheck(t *testing.T) {
+	for _, v := range []Catalog{
+		{Errors: []ErrorItem{{Name: "lower", Code: 1, Messages: map[string]string{"zh": "x"}}}},
+		{Errors: []ErrorItem{{Name: "A", Code: 1, Messages: map[string]string{"zh": "x"}}, {Name: "B", Code: 1, Messages: map[string]string{"zh": "x"}}}},
+		{Errors: []ErrorItem{{Name: "A", Code: 1}}},
+	} {
+		if err := v.fix(); err == nil {
+			t.Errorf("fix(%+v) 应该报错", v)
+		}
+	}
+}
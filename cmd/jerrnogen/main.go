@@ -0,0 +1,74 @@
+// jerrnogen 从错误码目录文件（yaml、toml）生成 jerrno 错误变量，以及 markdown 文档
+//
+// 用法：
+//
+//	//go:generate go run github.com/xtulnx/jkit-go/cmd/jerrnogen -in errors.yaml -out errors_gen.go -md ERRORS.md
+//
+// 目录文件的格式见 Catalog
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		in  = flag.String("in", "errors.yaml", "错误码目录文件，yaml 或 toml")
+		out = flag.String("out", "errors_gen.go", "生成的 go 文件，- 表示输出到控制台")
+		md  = flag.String("md", "", "生成的 markdown 文档，为空不生成")
+		pkg = flag.String("pkg", "", "生成的包名，默认用目录文件中的 package，其次是 $GOPACKAGE")
+	)
+	flag.Parse()
+
+	if err := run(*in, *out, *md, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "jerrnogen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, md, pkg string) error {
+	c, err := LoadCatalog(in)
+	if err != nil {
+		return err
+	}
+	if pkg == "" {
+		pkg = c.Package
+	}
+	if pkg == "" {
+		pkg = os.Getenv("GOPACKAGE")
+	}
+	if pkg == "" {
+		return fmt.Errorf("缺少包名，用 -pkg 指定")
+	}
+
+	var b bytes.Buffer
+	if err = GenerateGo(&b, c, pkg, filepath.Base(in)); err != nil {
+		return err
+	}
+	if err = writeOutput(out, b.Bytes()); err != nil {
+		return err
+	}
+
+	if md != "" {
+		b.Reset()
+		if err = GenerateMarkdown(&b, c); err != nil {
+			return err
+		}
+		if err = writeOutput(md, b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOutput(name string, data []byte) error {
+	if name == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
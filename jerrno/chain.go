@@ -0,0 +1,111 @@
+package jerrno
+
+import "errors"
+
+// 支持 errors.Is / errors.As，沿错误链按错误码比较。
+//
+//	errors.Is(fmt.Errorf("查询订单: %w", QueryNotFound.WithError(err)), QueryNotFound) == true
+
+// Coder 带错误码的错误
+type Coder interface {
+	error
+	ErrorCode() int
+}
+
+// HttpCoder 带http状态码的错误
+type HttpCoder interface {
+	error
+	ErrorHttpCode() int
+}
+
+// Is 没有错误码的，按信息比较
+func (e error0) Is(target error) bool {
+	if t, ok := target.(*error0); ok {
+		return t.Msg == e.Msg
+	}
+	return false
+}
+
+// Is 按错误码比较
+func (e error1) Is(target error) bool {
+	switch t := target.(type) {
+	case *error1:
+		return t.Code == e.Code
+	case *error2:
+		return t.Code == e.Code
+	}
+	return false
+}
+
+// Is 按错误码比较，双方都有http码时，http码也要相同
+func (e error2) Is(target error) bool {
+	switch t := target.(type) {
+	case *error1:
+		return t.Code == e.Code
+	case *error2:
+		return t.Code == e.Code && (t.HttpCode == 0 || e.HttpCode == 0 || t.HttpCode == e.HttpCode)
+	}
+	return false
+}
+
+func (e *error0) As(target any) bool {
+	return asErrEx(e, target)
+}
+
+func (e *error1) As(target any) bool {
+	return asErrEx(e, target)
+}
+
+func (e *error2) As(target any) bool {
+	return asErrEx(e, target)
+}
+
+func asErrEx(e ErrEx, target any) bool {
+	if p, ok := target.(*ErrEx); ok && p != nil {
+		*p = e
+		return true
+	}
+	return false
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// CoderOf 沿错误链查找第一个带错误码的错误，没有则返回 nil
+func CoderOf(err error) Coder {
+	var ex Coder
+	if err != nil && errors.As(err, &ex) {
+		return ex
+	}
+	return nil
+}
+
+// CodeOf 沿错误链查找错误码
+func CodeOf(err error) (code int, ok bool) {
+	if ex := CoderOf(err); ex != nil {
+		return ex.ErrorCode(), true
+	}
+	return 0, false
+}
+
+// HttpCodeOf 沿错误链查找http状态码，0 视为未指定
+func HttpCodeOf(err error) (httpCode int, ok bool) {
+	for err != nil {
+		if ex, ok1 := err.(HttpCoder); ok1 && ex.ErrorHttpCode() != 0 {
+			return ex.ErrorHttpCode(), true
+		}
+		err = errors.Unwrap(err)
+	}
+	return 0, false
+}
+
+// FromError 沿错误链查找 ErrEx，优先返回带错误码的
+func FromError(err error) (ErrEx, bool) {
+	if ex, ok := CoderOf(err).(ErrEx); ok {
+		return ex, true
+	}
+	var ex ErrEx
+	if err != nil && errors.As(err, &ex) {
+		return ex, true
+	}
+	return nil, false
+}
@@ -0,0 +1,60 @@
+package jerrno
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestErrorsIs(t *testing.T) {
+	e404 := newErrWithHttpCode(404, 550, "订单不存在")
+	for _, v := range []struct {
+		n      string
+		err    error
+		target error
+		want   bool
+	}{
+		{"WithError 后", QueryNotFound.WithError(io.EOF), QueryNotFound, true},
+		{"WithMsg 后", QueryNotFound.WithMsg("订单不存在"), QueryNotFound, true},
+		{"fmt 包装", fmt.Errorf("查询订单: %w", QueryNotFound.WithError(io.EOF)), QueryNotFound, true},
+		{"原始错误", fmt.Errorf("查询订单: %w", QueryNotFound.WithError(io.EOF)), io.EOF, true},
+		{"错误码不同", QueryFailed.WithError(io.EOF), QueryNotFound, false},
+		{"http码未指定", e404, QueryNotFound, true},
+		{"http码不同", e404, newErrWithHttpCode(500, 550, ""), false},
+		{"http码相同", e404.WithMsg("x"), newErrWithHttpCode(404, 550, ""), true},
+		{"WithHttpCode 后", WithHttpCode(QueryNotFound, 404), e404, true},
+		{"无错误码按信息", NewErr("a").WithError(io.EOF), NewErr("a"), true},
+		{"无错误码与有错误码", NewErr("记录并不存在"), QueryNotFound, false},
+	} {
+		if got := errors.Is(v.err, v.target); got != v.want {
+			t.Errorf("%s: errors.Is(%v, %v) = %v, want %v", v.n, v.err, v.target, got, v.want)
+		}
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	err := fmt.Errorf("保存: %w", NewErrWithError("更新失败", FailedUpdate.WithError(io.EOF)))
+	if code, ok := CodeOf(err); !ok || code != 553 {
+		t.Errorf("CodeOf() = %d, %v", code, ok)
+	}
+	if _, ok := CodeOf(io.EOF); ok {
+		t.Error("CodeOf(io.EOF) 应该没有错误码")
+	}
+	if code, ok := HttpCodeOf(fmt.Errorf("x: %w", newErrWithHttpCode(404, 1, "x"))); !ok || code != 404 {
+		t.Errorf("HttpCodeOf() = %d, %v", code, ok)
+	}
+	if code, ok := HttpCodeOf(WithHttpCode(BadRequest.WithMsg("x"), 422)); !ok || code != 422 {
+		t.Errorf("HttpCodeOf(WithHttpCode) = %d, %v", code, ok)
+	}
+	if e0 := NewErr("无错误码"); WithHttpCode(e0, 422) != e0 {
+		t.Error("WithHttpCode 没有错误码时应该原样返回")
+	}
+	if ex, ok := FromError(err); !ok || !errors.Is(ex, FailedUpdate) {
+		t.Errorf("FromError() = %v", ex)
+	}
+	var ex ErrEx
+	if !errors.As(err, &ex) {
+		t.Error("errors.As(*ErrEx) 失败")
+	}
+}
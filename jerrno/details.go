@@ -0,0 +1,73 @@
+package jerrno
+
+import "errors"
+
+// 错误的详细信息与字段错误，如表单校验时一次返回多个字段的问题
+//
+//	BadRequest.WithFieldError("mobile", "手机号格式有误").WithFieldError("age", "年龄需大于等于18")
+
+// FieldError 字段错误
+type FieldError struct {
+	Field string `json:"field"` // 字段名，一般是 json/form 的名称
+	Msg   string `json:"msg"`   // 错误信息
+}
+
+// ErrorDetails 详细信息
+func (e error0) ErrorDetails() map[string]any {
+	return e.Details
+}
+
+// ErrorFields 字段错误
+func (e error0) ErrorFields() []FieldError {
+	return e.Fields
+}
+
+// withDetail 复制后再修改，不影响原来的错误
+func (e error0) withDetail(key string, val any) error0 {
+	m := make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		m[k] = v
+	}
+	m[key] = val
+	e.Details = m
+	return e
+}
+
+// withFieldError 复制后再修改，不影响原来的错误
+func (e error0) withFieldError(field, msg string) error0 {
+	fields := make([]FieldError, len(e.Fields), len(e.Fields)+1)
+	copy(fields, e.Fields)
+	e.Fields = append(fields, FieldError{Field: field, Msg: msg})
+	return e
+}
+
+// DetailsOf 沿错误链合并详细信息，外层的优先
+func DetailsOf(err error) map[string]any {
+	var m map[string]any
+	for ; err != nil; err = errors.Unwrap(err) {
+		ex, ok := err.(interface{ ErrorDetails() map[string]any })
+		if !ok {
+			continue
+		}
+		for k, v := range ex.ErrorDetails() {
+			if m == nil {
+				m = make(map[string]any)
+			}
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+	}
+	return m
+}
+
+// FieldErrorsOf 沿错误链收集字段错误
+func FieldErrorsOf(err error) []FieldError {
+	var fields []FieldError
+	for ; err != nil; err = errors.Unwrap(err) {
+		if ex, ok := err.(interface{ ErrorFields() []FieldError }); ok {
+			fields = append(fields, ex.ErrorFields()...)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,31 @@
+package jerrno
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDetails(t *testing.T) {
+	e1 := BadRequest.WithFieldError("mobile", "手机号格式有误").WithDetail("form", "register")
+	e2 := e1.WithFieldError("age", "年龄需大于等于18").WithMsg("注册信息有误")
+
+	if len(BadRequest.(*error1).Fields) != 0 || len(e1.(*error1).Fields) != 1 {
+		t.Errorf("With* 修改了原来的错误")
+	}
+	want := []FieldError{{"mobile", "手机号格式有误"}, {"age", "年龄需大于等于18"}}
+	if got := FieldErrorsOf(e2); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldErrorsOf() = %v, want %v", got, want)
+	}
+
+	e3 := fmt.Errorf("包装: %w", newErrWithHttpCode(400, 1, "外层").WithDetail("form", "login").WithError(e2))
+	if got := DetailsOf(e3); !reflect.DeepEqual(got, map[string]any{"form": "login"}) {
+		t.Errorf("DetailsOf() = %v", got)
+	}
+	if got := FieldErrorsOf(e3); len(got) != 2 {
+		t.Errorf("FieldErrorsOf() = %v", got)
+	}
+	if got := DetailsOf(NewErr("x")); got != nil {
+		t.Errorf("DetailsOf() = %v", got)
+	}
+}
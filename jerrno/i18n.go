@@ -0,0 +1,203 @@
+package jerrno
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 多语言的错误信息，按 语言 + 错误码 查找，找不到时使用错误自带的信息。
+//
+// 文件格式（yaml 示例，toml、json 结构相同）：
+//
+//	en:
+//	  400: Bad request
+//	  401: Please login first
+//	zh-TW:
+//	  400: 參數有誤
+
+// Messages 默认的多语言信息
+var Messages = NewMessageCatalog()
+
+// DefaultLocale 错误自带信息（登记时的信息）的语言，请求的语言先匹配到它时不再查找 Messages
+var DefaultLocale = "zh"
+
+// MessageCatalog 多语言信息目录
+type MessageCatalog struct {
+	mu   sync.RWMutex
+	msgs map[string]map[int]string // locale -> code -> msg
+}
+
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{msgs: make(map[string]map[int]string)}
+}
+
+// NormalizeLocale 统一语言标记的格式，如 zh_CN => zh-cn
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Set 设置某个语言下错误码对应的信息
+func (m *MessageCatalog) Set(locale string, code int, msg string) {
+	m.SetMessages(locale, map[int]string{code: msg})
+}
+
+// SetMessages 批量设置某个语言的信息
+func (m *MessageCatalog) SetMessages(locale string, msgs map[int]string) {
+	locale = NormalizeLocale(locale)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m1, ok := m.msgs[locale]
+	if !ok {
+		m1 = make(map[int]string, len(msgs))
+		m.msgs[locale] = m1
+	}
+	for k, v := range msgs {
+		m1[k] = v
+	}
+}
+
+// Delete 删除某个语言下错误码对应的信息
+func (m *MessageCatalog) Delete(locale string, code int) {
+	locale = NormalizeLocale(locale)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m1, ok := m.msgs[locale]; ok {
+		delete(m1, code)
+		if len(m1) == 0 {
+			delete(m.msgs, locale)
+		}
+	}
+}
+
+// Locales 已有的语言
+func (m *MessageCatalog) Locales() []string {
+	m.mu.RLock()
+	ss := make([]string, 0, len(m.msgs))
+	for k := range m.msgs {
+		ss = append(ss, k)
+	}
+	m.mu.RUnlock()
+	sort.Strings(ss)
+	return ss
+}
+
+// Lookup 按语言的优先顺序查找，每个语言先精确匹配，再匹配主语言，如 en-us => en
+func (m *MessageCatalog) Lookup(code int, locales ...string) (string, bool) {
+	if len(locales) == 0 {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.msgs) == 0 {
+		return "", false
+	}
+	for _, l := range locales {
+		l = NormalizeLocale(l)
+		if msg, ok := m.msgs[l][code]; ok {
+			return msg, true
+		}
+		if i := strings.IndexByte(l, '-'); i > 0 {
+			if msg, ok := m.msgs[l[:i]][code]; ok {
+				return msg, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Load 从 yaml、toml、json 格式加载
+//
+//	format: yaml(yml)、toml、json
+func (m *MessageCatalog) Load(r io.Reader, format string) error {
+	var raw map[string]map[string]string
+	var err error
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "yaml", "yml":
+		err = yaml.NewDecoder(r).Decode(&raw)
+	case "toml":
+		_, err = toml.NewDecoder(r).Decode(&raw)
+	case "json":
+		err = json.NewDecoder(r).Decode(&raw)
+	default:
+		return fmt.Errorf("jerrno: 不支持的格式 %q", format)
+	}
+	if err != nil && err != io.EOF {
+		return err
+	}
+	for locale, msgs := range raw {
+		m1 := make(map[int]string, len(msgs))
+		for k, v := range msgs {
+			code, err := strconv.Atoi(strings.TrimSpace(k))
+			if err != nil {
+				return fmt.Errorf("jerrno: 语言 %s 的错误码 %q 无效", locale, k)
+			}
+			m1[code] = v
+		}
+		m.SetMessages(locale, m1)
+	}
+	return nil
+}
+
+// LoadFile 从文件加载，按扩展名识别格式
+func (m *MessageCatalog) LoadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Load(f, filepath.Ext(name))
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// LoadMessages 加载到默认的多语言信息
+func LoadMessages(r io.Reader, format string) error {
+	return Messages.Load(r, format)
+}
+
+// LoadMessagesFile 从文件加载到默认的多语言信息
+func LoadMessagesFile(name string) error {
+	return Messages.LoadFile(name)
+}
+
+// Localize 按语言获取错误信息，错误码沿错误链查找，没有错误码、找不到对应语言或先匹配到 DefaultLocale 时，返回 e.Error()。
+// 错误码已登记时，只有信息仍是登记的默认信息才使用多语言的，WithMsg 等修改过的信息保持不变。
+// 多语言的信息也可以是模板，用错误的参数替换，见 WithArgs
+func Localize(e error, locales ...string) string {
+	if e == nil {
+		return ""
+	}
+	if ex := CoderOf(e); ex != nil && isDefaultMsg(ex) {
+		def := NormalizeLocale(DefaultLocale)
+		for _, l := range locales {
+			if msg, ok := Messages.Lookup(ex.ErrorCode(), l); ok {
+				return Render(msg, ArgsOf(e))
+			}
+			if l = NormalizeLocale(l); def != "" && (l == def || strings.HasPrefix(l, def+"-")) {
+				break
+			}
+		}
+	}
+	return e.Error()
+}
+
+// isDefaultMsg 错误的信息模板与 DefaultRegistry 中登记的相同，未登记的错误码没有默认信息，视为相同
+func isDefaultMsg(ex Coder) bool {
+	reg, ok := Lookup(ex.ErrorCode())
+	if !ok {
+		return true
+	}
+	t, ok := ex.(interface{ Template() string })
+	t1, ok1 := reg.(interface{ Template() string })
+	return ok && ok1 && t.Template() == t1.Template()
+}
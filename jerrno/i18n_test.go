@@ -0,0 +1,89 @@
+package jerrno
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessageCatalog(t *testing.T) {
+	m := NewMessageCatalog()
+	for _, v := range []struct {
+		format string
+		data   string
+	}{
+		{"yaml", "en:\n  400: Bad request\n  401: Please login first\n"},
+		{"toml", "[zh-TW]\n400 = \"參數有誤\"\n"},
+		{".json", `{"ja":{"401":"ログインしてください"}}`},
+	} {
+		if err := m.Load(strings.NewReader(v.data), v.format); err != nil {
+			t.Fatalf("Load(%s) = %v", v.format, err)
+		}
+	}
+	if err := m.Load(strings.NewReader("en:\n  abc: x\n"), "yaml"); err == nil {
+		t.Error("无效错误码没有报错")
+	}
+
+	for _, v := range []struct {
+		code    int
+		locales []string
+		want    string
+		ok      bool
+	}{
+		{400, []string{"en"}, "Bad request", true},
+		{400, []string{"en-US"}, "Bad request", true},
+		{400, []string{"zh_TW"}, "參數有誤", true},
+		{401, []string{"fr", "ja-JP", "en"}, "ログインしてください", true},
+		{403, []string{"en"}, "", false},
+		{400, nil, "", false},
+	} {
+		if msg, ok := m.Lookup(v.code, v.locales...); msg != v.want || ok != v.ok {
+			t.Errorf("Lookup(%d, %v) = %q, %v, want %q", v.code, v.locales, msg, ok, v.want)
+		}
+	}
+	if l := m.Locales(); strings.Join(l, ",") != "en,ja,zh-tw" {
+		t.Errorf("Locales() = %v", l)
+	}
+	m.Delete("ja", 401)
+	if _, ok := m.Lookup(401, "ja"); ok || strings.Join(m.Locales(), ",") != "en,zh-tw" {
+		t.Errorf("Delete() 后 Locales() = %v", m.Locales())
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	setMessage(t, "en", 403, "Permission denied")
+	if s := Localize(Forbidden, "en-GB"); s != "Permission denied" {
+		t.Errorf("Localize(Forbidden) = %q", s)
+	}
+	for _, locales := range [][]string{{"zh-CN", "en"}, {"fr", "zh", "en"}} {
+		if s := Localize(Forbidden, locales...); s != "权限不足" {
+			t.Errorf("Localize(Forbidden, %v) = %q", locales, s)
+		}
+	}
+	if s := Localize(Forbidden, "fr"); s != "权限不足" {
+		t.Errorf("Localize(Forbidden, fr) = %q", s)
+	}
+	if s := Localize(Forbidden.WithArgs("id", 1).WithError(io.EOF), "en"); s != "Permission denied" {
+		t.Errorf("Localize(Forbidden.WithArgs) = %q", s)
+	}
+	if s := Localize(Forbidden.WithMsg("只读账号不能修改"), "en"); s != "只读账号不能修改" {
+		t.Errorf("Localize(Forbidden.WithMsg) = %q", s)
+	}
+	if s := Localize(NewErr("无错误码"), "en"); s != "无错误码" {
+		t.Errorf("Localize(NewErr) = %q", s)
+	}
+}
+
+// setMessage 设置默认的多语言信息，测试结束后恢复
+func setMessage(t *testing.T, locale string, code int, msg string) {
+	t.Helper()
+	old, ok := Messages.Lookup(code, locale)
+	t.Cleanup(func() {
+		if ok {
+			Messages.Set(locale, code, old)
+		} else {
+			Messages.Delete(locale, code)
+		}
+	})
+	Messages.Set(locale, code, msg)
+}
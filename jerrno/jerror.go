@@ -13,10 +13,15 @@ type ErrEx interface {
 	WithMsg(msg string) ErrEx                  // 附加错误信息
 	WithMsgAndError(msg string, e error) ErrEx // 附加错误信息和错误
 	IsSame(e error) bool                       // 判断是否是同类型的错误，只按低级别判断
+	WithDetail(key string, val any) ErrEx      // 附加详细信息
+	WithFieldError(field, msg string) ErrEx    // 附加字段错误，如表单校验
+	WithArgs(args ...any) ErrEx                // 附加信息模板的参数，map 或 key、value 交替
+	WithSeverity(s Severity) ErrEx             // 指定严重程度，默认按错误码推断
+	WithRetryable(retryable bool) ErrEx        // 指定是否可以重试，默认按错误码推断
 }
 
 // 这里定义一些常用异常，可以直接使用
-// 也可以自行 NewErrWithCode(0, "OK") 定义
+// 也可以自行 NewErrWithCode(0, "OK") 定义，包级变量的错误码会登记到 DefaultRegistry
 
 var (
 	OK                  = NewErrWithCode(0, "OK")     //
@@ -26,6 +31,7 @@ var (
 	Conflict            = NewErrWithCode(409, "记录已经存在")
 	TooManyRequests     = NewErrWithCode(429, "访问太快，请稍候再试") //
 	InternalServerError = NewErrWithCode(500, "服务器错误")      //
+	GatewayTimeout      = NewErrWithCode(504, "处理超时，请稍候再试")
 	QueryNotFound       = NewErrWithCode(550, "记录并不存在")
 	QueryFailed         = NewErrWithCode(551, "查询记录失败")
 	ConvertDataFailed   = NewErrWithCode(552, "数据格式有误")
@@ -33,7 +39,8 @@ var (
 )
 
 func NewErr(msg string) ErrEx {
-	return &error0{Msg: msg}
+	e0 := newError0(msg, nil)
+	return &e0
 }
 
 // NewErrWithError 带信息、附加异常
@@ -41,15 +48,24 @@ func NewErr(msg string) ErrEx {
 //	msg: 信息
 //	e: 附加异常
 func NewErrWithError(msg string, e error) ErrEx {
-	return &error0{Msg: msg, Err: e}
+	e0 := newError0(msg, e)
+	return &e0
 }
 
-// NewErrWithCode 带状态码、信息
+// NewErrWithCode 带状态码、信息，在包初始化时创建的登记到 DefaultRegistry 的默认模块
 //
 //	code: 状态码
 //	msg: 错误信息
 func NewErrWithCode(code int, msg string) ErrEx {
-	return &error1{Code: code, error0: error0{Msg: msg}}
+	e := newErrWithCode(code, msg)
+	if inPackageInit() {
+		_ = DefaultRegistry.Register(ModuleDefault, e)
+	}
+	return e
+}
+
+func newErrWithCode(code int, msg string) ErrEx {
+	return &error1{Code: code, error0: newError0(msg, nil)}
 }
 
 // NewErrWithCodeAndError 带状态码、信息、附加异常
@@ -58,21 +74,56 @@ func NewErrWithCode(code int, msg string) ErrEx {
 //	msg: 信息
 //	e: 附加异常
 func NewErrWithCodeAndError(code int, msg string, e error) ErrEx {
-	return &error1{Code: code, error0: error0{Msg: msg, Err: e}}
+	return &error1{Code: code, error0: newError0(msg, e)}
 }
 
-// NewErrWithHttpCode 创建带http码、状态码、错误信息的错误
+// NewErrWithHttpCode 创建带http码、状态码、错误信息的错误，在包初始化时创建的登记到 DefaultRegistry 的默认模块
 //
 //	httpCode: http状态码
 //	code: 状态码
 //	msg: 错误信息
 func NewErrWithHttpCode(httpCode, code int, msg string) ErrEx {
-	return &error2{HttpCode: httpCode, error1: error1{Code: code, error0: error0{Msg: msg}}}
+	e := newErrWithHttpCode(httpCode, code, msg)
+	if inPackageInit() {
+		_ = DefaultRegistry.Register(ModuleDefault, e)
+	}
+	return e
+}
+
+func newErrWithHttpCode(httpCode, code int, msg string) ErrEx {
+	return &error2{HttpCode: httpCode, error1: error1{Code: code, error0: newError0(msg, nil)}}
+}
+
+// WithHttpCode 复制错误并指定http状态码，如 WithHttpCode(BadRequest, 422)。
+// http状态码依附于错误码，e 没有错误码时（如 NewErr 创建的）不做修改，原样返回
+func WithHttpCode(e ErrEx, httpCode int) ErrEx {
+	if ex, ok := e.(interface{ withHttpCode(httpCode int) ErrEx }); ok {
+		return ex.withHttpCode(httpCode)
+	}
+	return e
 }
 
 type error0 struct {
-	Msg string
-	Err error
+	Msg     string
+	Err     error
+	Args    map[string]any // 信息模板的参数，Msg 中的 {name} 在输出时替换
+	Details map[string]any // 详细信息
+	Fields  []FieldError   // 字段错误
+	stack   Stack          // 创建时的调用栈，需要 EnableStack 开启
+
+	severity  Severity // 严重程度，0 表示按错误码推断
+	retryable int8     // 是否可以重试，0 表示按错误码推断，1 是，-1 否
+}
+
+// newError0 创建错误信息，并记录调用栈
+func newError0(msg string, err error) error0 {
+	return error0{Msg: msg, Err: err, stack: callers()}
+}
+
+// derive 基于当前错误创建新的错误信息，调用栈按新的位置记录
+func (e error0) derive(msg string, err error) error0 {
+	return error0{Msg: msg, Err: err, Args: e.Args, Details: e.Details, Fields: e.Fields, stack: callers(),
+		severity: e.severity, retryable: e.retryable}
 }
 
 type error1 struct {
@@ -88,6 +139,9 @@ func (e error0) Unwrap() error {
 	return e.Err
 }
 func (e error0) Error() string {
+	if len(e.Args) > 0 {
+		return Render(e.Msg, e.Args)
+	}
 	return e.Msg
 }
 func (e error1) ErrorCode() int {
@@ -115,30 +169,35 @@ func (e error0) CombineError(e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error0{Msg: e.Msg, Err: e1}
+	e0 := e.derive(e.Msg, e1)
+	return &e0
 }
 
 func (e error0) CombineErrorMsg(msg string, e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error0{Msg: msg, Err: e1}
+	e0 := e.derive(msg, e1)
+	return &e0
 }
 
 func (e error0) WithError(e1 error) ErrEx {
-	return &error0{Msg: e.Msg, Err: e1}
+	e0 := e.derive(e.Msg, e1)
+	return &e0
 }
 
 func (e error0) WithCode(code int) ErrEx {
-	return &error1{Code: code, error0: error0{Msg: e.Msg, Err: e.Err}}
+	return &error1{Code: code, error0: e.derive(e.Msg, e.Err)}
 }
 
 func (e error0) WithMsg(msg string) ErrEx {
-	return &error0{Msg: msg, Err: e.Err}
+	e0 := e.derive(msg, e.Err)
+	return &e0
 }
 
 func (e error0) WithMsgAndError(msg string, e1 error) ErrEx {
-	return &error0{Msg: msg, Err: e1}
+	e0 := e.derive(msg, e1)
+	return &e0
 }
 
 func (e error0) IsSame(e1 error) bool {
@@ -148,36 +207,66 @@ func (e error0) IsSame(e1 error) bool {
 	return false
 }
 
+func (e error0) WithDetail(key string, val any) ErrEx {
+	e0 := e.derive(e.Msg, e.Err).withDetail(key, val)
+	return &e0
+}
+
+func (e error0) WithFieldError(field, msg string) ErrEx {
+	e0 := e.derive(e.Msg, e.Err).withFieldError(field, msg)
+	return &e0
+}
+
+func (e error0) WithArgs(args ...any) ErrEx {
+	e0 := e.derive(e.Msg, e.Err).withArgs(args...)
+	return &e0
+}
+
+func (e error0) WithSeverity(sev Severity) ErrEx {
+	e0 := e.derive(e.Msg, e.Err)
+	e0.severity = sev
+	return &e0
+}
+
+func (e error0) WithRetryable(retryable bool) ErrEx {
+	e0 := e.derive(e.Msg, e.Err).withRetryable(retryable)
+	return &e0
+}
+
 // -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
 
 func (e error1) CombineError(e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error1{Code: e.Code, error0: error0{Msg: e.Msg, Err: e1}}
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e1)}
 }
 
 func (e error1) CombineErrorMsg(msg string, e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error1{Code: e.Code, error0: error0{Msg: msg, Err: e1}}
+	return &error1{Code: e.Code, error0: e.derive(msg, e1)}
 }
 
 func (e error1) WithError(e1 error) ErrEx {
-	return &error1{Code: e.Code, error0: error0{Msg: e.Msg, Err: e1}}
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e1)}
 }
 
 func (e error1) WithCode(code int) ErrEx {
-	return &error1{Code: code, error0: error0{Msg: e.Msg, Err: e.Err}}
+	return &error1{Code: code, error0: e.derive(e.Msg, e.Err)}
+}
+
+func (e error1) withHttpCode(httpCode int) ErrEx {
+	return &error2{HttpCode: httpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err)}}
 }
 
 func (e error1) WithMsg(msg string) ErrEx {
-	return &error1{Code: e.Code, error0: error0{Msg: msg, Err: e.Err}}
+	return &error1{Code: e.Code, error0: e.derive(msg, e.Err)}
 }
 
 func (e error1) WithMsgAndError(msg string, e1 error) ErrEx {
-	return &error1{Code: e.Code, error0: error0{Msg: msg, Err: e1}}
+	return &error1{Code: e.Code, error0: e.derive(msg, e1)}
 }
 
 func (e error1) IsSame(e1 error) bool {
@@ -187,36 +276,62 @@ func (e error1) IsSame(e1 error) bool {
 	return false
 }
 
+func (e error1) WithDetail(key string, val any) ErrEx {
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withDetail(key, val)}
+}
+
+func (e error1) WithFieldError(field, msg string) ErrEx {
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withFieldError(field, msg)}
+}
+
+func (e error1) WithArgs(args ...any) ErrEx {
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withArgs(args...)}
+}
+
+func (e error1) WithSeverity(sev Severity) ErrEx {
+	e0 := e.derive(e.Msg, e.Err)
+	e0.severity = sev
+	return &error1{Code: e.Code, error0: e0}
+}
+
+func (e error1) WithRetryable(retryable bool) ErrEx {
+	return &error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withRetryable(retryable)}
+}
+
 // -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
 
 func (e error2) CombineError(e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: error0{Msg: e.Msg, Err: e1}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e1)}}
 }
 
 func (e error2) CombineErrorMsg(msg string, e1 error) ErrEx {
 	if ex := isInnerError(e1); ex != nil {
 		return ex
 	}
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: error0{Msg: msg, Err: e1}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(msg, e1)}}
 }
 
 func (e error2) WithError(e1 error) ErrEx {
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: error0{Msg: e.Msg, Err: e1}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e1)}}
 }
 
 func (e error2) WithCode(code int) ErrEx {
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: code, error0: error0{Msg: e.Msg, Err: e.Err}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: code, error0: e.derive(e.Msg, e.Err)}}
+}
+
+func (e error2) withHttpCode(httpCode int) ErrEx {
+	return &error2{HttpCode: httpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err)}}
 }
 
 func (e error2) WithMsg(msg string) ErrEx {
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: error0{Msg: msg, Err: e.Err}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(msg, e.Err)}}
 }
 
 func (e error2) WithMsgAndError(msg string, e1 error) ErrEx {
-	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: error0{Msg: msg, Err: e1}}}
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(msg, e1)}}
 }
 
 func (e error2) IsSame(e1 error) bool {
@@ -225,3 +340,25 @@ func (e error2) IsSame(e1 error) bool {
 	}
 	return false
 }
+
+func (e error2) WithDetail(key string, val any) ErrEx {
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withDetail(key, val)}}
+}
+
+func (e error2) WithFieldError(field, msg string) ErrEx {
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withFieldError(field, msg)}}
+}
+
+func (e error2) WithArgs(args ...any) ErrEx {
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withArgs(args...)}}
+}
+
+func (e error2) WithSeverity(sev Severity) ErrEx {
+	e0 := e.derive(e.Msg, e.Err)
+	e0.severity = sev
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e0}}
+}
+
+func (e error2) WithRetryable(retryable bool) ErrEx {
+	return &error2{HttpCode: e.HttpCode, error1: error1{Code: e.Code, error0: e.derive(e.Msg, e.Err).withRetryable(retryable)}}
+}
@@ -0,0 +1,93 @@
+package jerrno
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// 错误的 json 序列化，用于跨服务传递错误
+//
+//	{"code":550,"httpCode":404,"msg":"订单 1 不存在","args":{"id":1},"details":{},"fields":[],"cause":"record not found"}
+
+// errJSON 序列化格式
+type errJSON struct {
+	Code     int            `json:"code,omitempty"`
+	HttpCode int            `json:"httpCode,omitempty"`
+	Msg      string         `json:"msg"`
+	Args     map[string]any `json:"args,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+	Fields   []FieldError   `json:"fields,omitempty"`
+	Cause    string         `json:"cause,omitempty"`
+}
+
+func (e error0) toJSON() errJSON {
+	v := errJSON{Msg: e.Error(), Args: e.Args, Details: e.Details, Fields: e.Fields}
+	if e.Err != nil {
+		v.Cause = e.Err.Error()
+	}
+	return v
+}
+
+func (e error0) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON())
+}
+
+func (e error1) MarshalJSON() ([]byte, error) {
+	v := e.toJSON()
+	v.Code = e.Code
+	return json.Marshal(v)
+}
+
+func (e error2) MarshalJSON() ([]byte, error) {
+	v := e.toJSON()
+	v.Code, v.HttpCode = e.Code, e.HttpCode
+	return json.Marshal(v)
+}
+
+// UnmarshalErr 从 json 还原错误，错误码已登记时基于登记的错误还原，见 FromCode
+func UnmarshalErr(data []byte) (ErrEx, error) {
+	var v errJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	var ex ErrEx
+	if v.Code != 0 || v.HttpCode != 0 {
+		ex = FromCode(v.Code, v.HttpCode, v.Msg)
+	} else {
+		ex = NewErr(v.Msg)
+	}
+	if v.Cause != "" {
+		ex = ex.WithError(errors.New(v.Cause))
+	}
+	return WithExtra(ex, v.Args, v.Details, v.Fields), nil
+}
+
+// FromCode 按错误码还原错误，用于解析其他服务返回的结果。
+// 已登记的错误码，返回登记的错误（信息不同时替换为 msg），可以用 errors.Is 比较；
+// 未登记的创建新的错误，但不登记
+func FromCode(code, httpCode int, msg string) ErrEx {
+	if ex, ok := Lookup(code); ok {
+		if msg != "" && msg != ex.Error() {
+			return ex.WithMsg(msg)
+		}
+		return ex
+	}
+	if httpCode != 0 {
+		return newErrWithHttpCode(httpCode, code, msg)
+	}
+	return newErrWithCode(code, msg)
+}
+
+// WithExtra 附加模板参数、详细信息、字段错误
+func WithExtra(ex ErrEx, args, details map[string]any, fields []FieldError) ErrEx {
+	if len(args) > 0 {
+		ex = ex.WithArgs(args)
+	}
+	for k, v := range details {
+		ex = ex.WithDetail(k, v)
+	}
+	for _, f := range fields {
+		ex = ex.WithFieldError(f.Field, f.Msg)
+	}
+	return ex
+}
@@ -0,0 +1,55 @@
+package jerrno
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestErrJSON(t *testing.T) {
+	e := QueryNotFound.WithArgs("id", 1).WithDetail("table", "order").
+		WithFieldError("id", "不存在").WithError(errors.New("record not found"))
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"code":550,"msg":"记录并不存在","args":{"id":1},"details":{"table":"order"},"fields":[{"field":"id","msg":"不存在"}],"cause":"record not found"}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	ex, err := UnmarshalErr(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(ex, QueryNotFound) || ex.Error() != "记录并不存在" {
+		t.Errorf("UnmarshalErr() = %v", ex)
+	}
+	if d := DetailsOf(ex); d["table"] != "order" {
+		t.Errorf("UnmarshalErr() details = %v", d)
+	}
+	if f := FieldErrorsOf(ex); len(f) != 1 || f[0].Field != "id" {
+		t.Errorf("UnmarshalErr() fields = %v", f)
+	}
+	if c := errors.Unwrap(ex); c == nil || c.Error() != "record not found" {
+		t.Errorf("UnmarshalErr() cause = %v", c)
+	}
+	if SeverityOf(ex) != SeverityOf(QueryNotFound) {
+		t.Errorf("UnmarshalErr() severity = %v", SeverityOf(ex))
+	}
+
+	ex, _ = UnmarshalErr([]byte(`{"code":987654,"httpCode":409,"msg":"远程错误"}`))
+	if !errors.Is(ex, newErrWithCode(987654, "")) || ex.Error() != "远程错误" {
+		t.Errorf("UnmarshalErr() 未登记 = %v", ex)
+	}
+	if hc, _ := HttpCodeOf(ex); hc != 409 {
+		t.Errorf("UnmarshalErr() httpCode = %d", hc)
+	}
+	if _, ok := Lookup(987654); ok {
+		t.Error("UnmarshalErr() 不应登记")
+	}
+
+	if ex := FromCode(400, 0, "Bad request"); !errors.Is(ex, BadRequest) || ex.Error() != "Bad request" {
+		t.Errorf("FromCode() = %v", ex)
+	}
+}
@@ -0,0 +1,341 @@
+package jerrno
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 错误码登记：检查重复的错误码，按模块分组，导出错误码目录。
+//
+// 包级变量（包初始化时）用 NewErrWithCode、NewErrWithHttpCode 创建的错误会登记到 DefaultRegistry，
+// 处理请求时临时创建的不登记，避免被当作重复的错误码；
+// 模块内的错误用 Module("xxx").NewErrWithCode(...) 创建，总是登记。
+
+// ModuleDefault 未指定模块时使用的模块名
+const ModuleDefault = "common"
+
+// DupPolicy 错误码重复时的处理方式
+type DupPolicy int
+
+const (
+	DupReport DupPolicy = iota // 记录重复（每个错误码一次），保留先登记的（默认）
+	DupIgnore                  // 忽略重复
+	DupPanic                   // 直接 panic，适合在测试或启动时检查
+)
+
+// CatalogItem 错误码目录的一项
+type CatalogItem struct {
+	Code     int    `json:"code"`               // 业务错误码
+	HttpCode int    `json:"httpCode,omitempty"` // http状态码，0 表示未指定
+	Msg      string `json:"msg"`                // 错误信息
+	Module   string `json:"module"`             // 所属模块
+}
+
+// Duplicate 重复登记的错误码
+type Duplicate struct {
+	CatalogItem             // 后登记的
+	Exists      CatalogItem `json:"exists"` // 已经登记的
+}
+
+// DuplicateError 错误码重复
+type DuplicateError struct {
+	Duplicate
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("jerrno: 错误码 %d 重复, [%s]%s 与已登记的 [%s]%s 冲突",
+		e.Code, e.Module, e.Msg, e.Exists.Module, e.Exists.Msg)
+}
+
+type regItem struct {
+	CatalogItem
+	err ErrEx
+}
+
+// Registry 错误码登记表
+type Registry struct {
+	mu     sync.RWMutex
+	policy DupPolicy
+	items  map[int]*regItem
+	dups   []Duplicate
+	onDup  func(d Duplicate)
+
+	dupCodes map[int]struct{} // dups 中已有的错误码
+}
+
+// DefaultRegistry 默认的登记表
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{items: make(map[int]*regItem), dupCodes: make(map[int]struct{})}
+}
+
+// SetDupPolicy 设置错误码重复时的处理方式
+func (r *Registry) SetDupPolicy(p DupPolicy) {
+	r.mu.Lock()
+	r.policy = p
+	r.mu.Unlock()
+}
+
+// OnDuplicate 错误码重复时回调，如输出到日志
+func (r *Registry) OnDuplicate(fn func(d Duplicate)) {
+	r.mu.Lock()
+	r.onDup = fn
+	r.mu.Unlock()
+}
+
+// Register 登记错误，没有错误码的错误会被忽略。
+// 同一个错误码再次登记且内容不同时，返回 *DuplicateError，并按 DupPolicy 处理。
+func (r *Registry) Register(module string, e ErrEx) error {
+	return r.register(module, e, false)
+}
+
+// register 登记错误，replace 表示内容相同时用 e 替换已登记的，如附加了严重程度
+func (r *Registry) register(module string, e ErrEx, replace bool) error {
+	item, ok := catalogItemOf(module, e)
+	if !ok {
+		return nil
+	}
+
+	r.mu.Lock()
+	exists, found := r.items[item.Code]
+	if !found {
+		r.items[item.Code] = &regItem{CatalogItem: item, err: e}
+		r.mu.Unlock()
+		return nil
+	}
+	if exists.CatalogItem == item {
+		if replace {
+			exists.err = e
+		}
+		r.mu.Unlock()
+		return nil
+	}
+	d := Duplicate{CatalogItem: item, Exists: exists.CatalogItem}
+	policy, onDup := r.policy, r.onDup
+	if policy == DupReport {
+		// 每个错误码只记录第一次重复，避免重复登记时无限增长
+		if _, known := r.dupCodes[item.Code]; !known {
+			r.dupCodes[item.Code] = struct{}{}
+			r.dups = append(r.dups, d)
+		} else {
+			onDup = nil
+		}
+	}
+	r.mu.Unlock()
+
+	err := &DuplicateError{Duplicate: d}
+	switch policy {
+	case DupPanic:
+		panic(err)
+	case DupReport:
+		if onDup != nil {
+			onDup(d)
+		}
+	}
+	return err
+}
+
+// Lookup 按错误码查找已登记的错误
+func (r *Registry) Lookup(code int) (ErrEx, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if v, ok := r.items[code]; ok {
+		return v.err, true
+	}
+	return nil, false
+}
+
+// Duplicates 已发现的重复错误码，每个错误码只有第一次重复
+func (r *Registry) Duplicates() []Duplicate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Duplicate(nil), r.dups...)
+}
+
+// Modules 已登记的模块，按名称排序
+func (r *Registry) Modules() []string {
+	r.mu.RLock()
+	m := make(map[string]struct{})
+	for _, v := range r.items {
+		m[v.Module] = struct{}{}
+	}
+	r.mu.RUnlock()
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Catalog 错误码目录，按错误码排序。指定 modules 时只返回这些模块的
+func (r *Registry) Catalog(modules ...string) []CatalogItem {
+	r.mu.RLock()
+	items := make([]CatalogItem, 0, len(r.items))
+	for _, v := range r.items {
+		if len(modules) == 0 || containsString(modules, v.Module) {
+			items = append(items, v.CatalogItem)
+		}
+	}
+	r.mu.RUnlock()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Code < items[j].Code
+	})
+	return items
+}
+
+// ExportJSON 以 json 数组导出错误码目录
+func (r *Registry) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.Catalog())
+}
+
+// ExportMarkdown 以 markdown 表格导出错误码目录，按模块分节
+func (r *Registry) ExportMarkdown(w io.Writer) error {
+	return WriteMarkdownCatalog(w, r.Catalog())
+}
+
+// WriteMarkdownCatalog 把错误码目录写成 markdown 表格，按模块分节
+func WriteMarkdownCatalog(w io.Writer, items []CatalogItem) error {
+	var modules []string
+	groups := make(map[string][]CatalogItem)
+	for _, v := range items {
+		if _, ok := groups[v.Module]; !ok {
+			modules = append(modules, v.Module)
+		}
+		groups[v.Module] = append(groups[v.Module], v)
+	}
+	sort.Strings(modules)
+
+	var b strings.Builder
+	b.WriteString("# 错误码\n")
+	for _, m := range modules {
+		b.WriteString("\n## " + m + "\n\n")
+		b.WriteString("| 错误码 | HTTP 状态码 | 错误信息 |\n")
+		b.WriteString("| ---: | ---: | --- |\n")
+		for _, v := range groups[m] {
+			httpCode := "-"
+			if v.HttpCode != 0 {
+				httpCode = strconv.Itoa(v.HttpCode)
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s |\n", v.Code, httpCode, markdownEscaper.Replace(v.Msg))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+
+// inPackageInit 是否在包初始化中，即调用栈中有 runtime.doInit
+func inPackageInit() bool {
+	pc := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		f, more := frames.Next()
+		if strings.HasPrefix(f.Function, "runtime.doInit") {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func catalogItemOf(module string, e ErrEx) (CatalogItem, bool) {
+	if module == "" {
+		module = ModuleDefault
+	}
+	switch ex := e.(type) {
+	case *error1:
+		return CatalogItem{Code: ex.Code, Msg: ex.Msg, Module: module}, true
+	case *error2:
+		return CatalogItem{Code: ex.Code, HttpCode: ex.HttpCode, Msg: ex.Msg, Module: module}, true
+	}
+	return CatalogItem{}, false
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// ModuleErrs 按模块创建并登记错误
+type ModuleErrs struct {
+	name string
+	r    *Registry
+}
+
+// Module 在 DefaultRegistry 中按模块创建错误
+func Module(name string) *ModuleErrs {
+	return DefaultRegistry.Module(name)
+}
+
+// Module 在登记表中按模块创建错误
+func (r *Registry) Module(name string) *ModuleErrs {
+	return &ModuleErrs{name: name, r: r}
+}
+
+func (m *ModuleErrs) Name() string {
+	return m.name
+}
+
+// NewErrWithCode 创建并登记带状态码、信息的错误
+func (m *ModuleErrs) NewErrWithCode(code int, msg string) ErrEx {
+	e := newErrWithCode(code, msg)
+	_ = m.r.Register(m.name, e)
+	return e
+}
+
+// NewErrWithHttpCode 创建并登记带http码、状态码、错误信息的错误
+func (m *ModuleErrs) NewErrWithHttpCode(httpCode, code int, msg string) ErrEx {
+	e := newErrWithHttpCode(httpCode, code, msg)
+	_ = m.r.Register(m.name, e)
+	return e
+}
+
+// Register 登记附加了其他信息的错误，如 WithSeverity 之后的，
+// 错误码、信息与已登记的相同时替换，Lookup 返回替换后的错误
+//
+//	OrderNotFound = m.Register(m.NewErrWithCode(10001, "订单不存在").WithSeverity(jerrno.SeverityInfo))
+func (m *ModuleErrs) Register(e ErrEx) ErrEx {
+	_ = m.r.register(m.name, e, true)
+	return e
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// Lookup 在 DefaultRegistry 中按错误码查找
+func Lookup(code int) (ErrEx, bool) {
+	return DefaultRegistry.Lookup(code)
+}
+
+// Catalog DefaultRegistry 的错误码目录
+func Catalog(modules ...string) []CatalogItem {
+	return DefaultRegistry.Catalog(modules...)
+}
+
+// ExportJSON 导出 DefaultRegistry 的错误码目录
+func ExportJSON(w io.Writer) error {
+	return DefaultRegistry.ExportJSON(w)
+}
+
+// ExportMarkdown 导出 DefaultRegistry 的错误码目录
+func ExportMarkdown(w io.Writer) error {
+	return DefaultRegistry.ExportMarkdown(w)
+}
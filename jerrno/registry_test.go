@@ -0,0 +1,104 @@
+package jerrno
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	order := r.Module("order")
+	user := r.Module("user")
+
+	e1 := order.NewErrWithHttpCode(404, 10001, "订单不存在")
+	_ = user.NewErrWithCode(20001, "用户已禁用")
+	_ = order.NewErrWithCode(10001, "订单不存在") // 内容不同（缺少 http 码），算重复
+	_ = order.NewErrWithHttpCode(404, 10001, "订单不存在")
+
+	if ex, ok := r.Lookup(10001); !ok || ex != e1 {
+		t.Errorf("Lookup(10001) = %v, want %v", ex, e1)
+	}
+	if dups := r.Duplicates(); len(dups) != 1 || dups[0].Exists.HttpCode != 404 {
+		t.Errorf("Duplicates() = %+v", dups)
+	}
+	if err := r.Register("user", NewErr("无错误码")); err != nil {
+		t.Errorf("Register 无错误码 = %v", err)
+	}
+	var dupErr *DuplicateError
+	if err := r.Register("user", newErrWithCode(10001, "其他")); !errors.As(err, &dupErr) {
+		t.Errorf("Register 重复 = %v", err)
+	}
+	if dups := r.Duplicates(); len(dups) != 1 {
+		t.Errorf("同一错误码只记录一次 Duplicates() = %+v", dups)
+	}
+
+	if m := r.Modules(); strings.Join(m, ",") != "order,user" {
+		t.Errorf("Modules() = %v", m)
+	}
+	if c := r.Catalog("user"); len(c) != 1 || c[0].Code != 20001 {
+		t.Errorf("Catalog(user) = %v", c)
+	}
+
+	var b1 bytes.Buffer
+	if err := r.ExportJSON(&b1); err != nil {
+		t.Fatal(err)
+	}
+	var items []CatalogItem
+	if err := json.Unmarshal(b1.Bytes(), &items); err != nil || len(items) != 2 || items[0].Module != "order" {
+		t.Errorf("ExportJSON() = %s, %v", b1.String(), err)
+	}
+
+	var b2 bytes.Buffer
+	if err := r.ExportMarkdown(&b2); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b2.String(), "## order") || !strings.Contains(b2.String(), "| 10001 | 404 | 订单不存在 |") {
+		t.Errorf("ExportMarkdown() = %s", b2.String())
+	}
+
+	e3 := order.Register(order.NewErrWithCode(10002, "订单已支付").WithSeverity(SeverityInfo))
+	if ex, ok := r.Lookup(10002); !ok || ex != e3 {
+		t.Errorf("Register() 没有替换已登记的: %v", ex)
+	}
+
+	r.SetDupPolicy(DupPanic)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("DupPanic 没有 panic")
+			}
+		}()
+		_ = user.NewErrWithCode(20001, "另一个信息")
+	}()
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	if ex, ok := Lookup(550); !ok || ex != QueryNotFound {
+		t.Errorf("Lookup(550) = %v", ex)
+	}
+	if c := Catalog(ModuleDefault); len(c) < 11 {
+		t.Errorf("Catalog(%s) = %v", ModuleDefault, c)
+	}
+}
+
+var tInitErr = NewErrWithCode(19001, "初始化时登记")
+
+func TestRegisterOnInit(t *testing.T) {
+	if ex, ok := Lookup(19001); !ok || ex != tInitErr {
+		t.Errorf("包级变量没有登记: %v", ex)
+	}
+
+	DefaultRegistry.SetDupPolicy(DupPanic)
+	defer DefaultRegistry.SetDupPolicy(DupReport)
+	e := NewErrWithCode(400, "自定义") // 处理请求时临时创建，不登记
+	if ex, _ := Lookup(400); ex != BadRequest || e.(Coder).ErrorCode() != 400 {
+		t.Errorf("Lookup(400) = %v", ex)
+	}
+	_ = NewErrWithHttpCode(404, 19002, "临时")
+	if _, ok := Lookup(19002); ok {
+		t.Error("临时创建的错误被登记了")
+	}
+}
@@ -0,0 +1,175 @@
+package jerrno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 错误的严重程度与是否可以重试，用于决定日志级别、客户端是否重试。
+// 没有指定时按错误码推断，见 DefaultSeverity、DefaultRetryable
+
+// Severity 严重程度
+type Severity int8
+
+const (
+	SeverityUnset Severity = iota // 未指定，按错误码推断
+	SeverityDebug
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+)
+
+var severityNames = [...]string{"", "debug", "info", "warn", "error"}
+
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", s)
+}
+
+// ParseSeverity 解析 debug、info、warn(warning)、error，空字符串为未指定
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return SeverityUnset, nil
+	case "debug":
+		return SeverityDebug, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "error", "err":
+		return SeverityError, nil
+	}
+	return SeverityUnset, fmt.Errorf("jerrno: 无效的严重程度 %q", s)
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(data []byte) error {
+	v, err := ParseSeverity(string(data))
+	if err == nil {
+		*s = v
+	}
+	return err
+}
+
+// DefaultSeverity 按错误码推断严重程度，可以替换
+//
+//	0: debug
+//	429: warn
+//	4xx: info
+//	500~549: error
+//	其他（包括 550 之后的业务错误码）: warn
+var DefaultSeverity = func(code int) Severity {
+	switch {
+	case code == 0:
+		return SeverityDebug
+	case code == 429:
+		return SeverityWarn
+	case code >= 400 && code < 500:
+		return SeverityInfo
+	case code >= 500 && code < 550:
+		return SeverityError
+	}
+	return SeverityWarn
+}
+
+// DefaultRetryable 按错误码推断是否可以重试，可以替换
+//
+//	429、503、504 可以重试
+var DefaultRetryable = func(code int) bool {
+	switch code {
+	case 429, 503, 504:
+		return true
+	}
+	return false
+}
+
+func (e error0) withRetryable(retryable bool) error0 {
+	if retryable {
+		e.retryable = 1
+	} else {
+		e.retryable = -1
+	}
+	return e
+}
+
+// ErrorSeverity 严重程度，没有错误码时默认为 error
+func (e error0) ErrorSeverity() Severity {
+	if e.severity != SeverityUnset {
+		return e.severity
+	}
+	return SeverityError
+}
+
+// Retryable 是否可以重试，没有错误码时默认否
+func (e error0) Retryable() bool {
+	return e.retryable > 0
+}
+
+// Temporary 同 Retryable，与 net.Error 的约定一致
+func (e error0) Temporary() bool {
+	return e.Retryable()
+}
+
+func (e error1) ErrorSeverity() Severity {
+	if e.severity != SeverityUnset {
+		return e.severity
+	}
+	return DefaultSeverity(e.Code)
+}
+
+func (e error1) Retryable() bool {
+	if e.retryable != 0 {
+		return e.retryable > 0
+	}
+	return DefaultRetryable(e.Code)
+}
+
+func (e error1) Temporary() bool {
+	return e.Retryable()
+}
+
+func (e error2) ErrorSeverity() Severity {
+	return e.error1.ErrorSeverity()
+}
+
+func (e error2) Retryable() bool {
+	if e.retryable == 0 && e.HttpCode != 0 && DefaultRetryable(e.HttpCode) {
+		return true
+	}
+	return e.error1.Retryable()
+}
+
+func (e error2) Temporary() bool {
+	return e.Retryable()
+}
+
+// SeverityOf 错误的严重程度，沿错误链查找，优先用带错误码的；非 jerrno 的错误为 error
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return SeverityUnset
+	}
+	if ex, ok := FromError(err); ok {
+		if s, ok := ex.(interface{ ErrorSeverity() Severity }); ok {
+			return s.ErrorSeverity()
+		}
+	}
+	return SeverityError
+}
+
+// RetryableOf 是否可以重试，沿错误链查找，优先用带错误码的
+func RetryableOf(err error) bool {
+	if ex, ok := FromError(err); ok {
+		if r, ok := ex.(interface{ Retryable() bool }); ok {
+			return r.Retryable()
+		}
+	}
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
@@ -0,0 +1,42 @@
+package jerrno
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	for _, v := range []struct {
+		err       error
+		severity  Severity
+		retryable bool
+	}{
+		{nil, SeverityUnset, false},
+		{fmt.Errorf("其他错误"), SeverityError, false},
+		{NewErr("无错误码"), SeverityError, false},
+		{OK, SeverityDebug, false},
+		{BadRequest, SeverityInfo, false},
+		{TooManyRequests, SeverityWarn, true},
+		{InternalServerError, SeverityError, false},
+		{GatewayTimeout.WithError(fmt.Errorf("x")), SeverityError, true},
+		{QueryNotFound, SeverityWarn, false},
+		{fmt.Errorf("x: %w", QueryFailed.WithSeverity(SeverityError).WithRetryable(true)), SeverityError, true},
+		{TooManyRequests.WithRetryable(false).WithMsg("x"), SeverityWarn, false},
+		{newErrWithHttpCode(503, 10001, "维护中"), SeverityWarn, true},
+	} {
+		if s := SeverityOf(v.err); s != v.severity {
+			t.Errorf("SeverityOf(%v) = %v, want %v", v.err, s, v.severity)
+		}
+		if r := RetryableOf(v.err); r != v.retryable {
+			t.Errorf("RetryableOf(%v) = %v, want %v", v.err, r, v.retryable)
+		}
+	}
+
+	var s Severity
+	if err := s.UnmarshalText([]byte("Warning")); err != nil || s != SeverityWarn {
+		t.Errorf("UnmarshalText() = %v, %v", s, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(fatal) 应该报错")
+	}
+}
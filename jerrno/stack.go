@@ -0,0 +1,180 @@
+package jerrno
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// 创建错误时记录调用栈，默认关闭，用 EnableStack(true) 开启。
+//
+// 用 %+v 输出错误时，包含 信息、错误码、http码、原始错误链、调用栈
+
+const maxStackDepth = 32
+
+var stackEnabled atomic.Bool
+
+// EnableStack 全局开启或关闭调用栈记录，只影响之后创建的错误
+func EnableStack(on bool) {
+	stackEnabled.Store(on)
+}
+
+// IsStackEnabled 是否记录调用栈
+func IsStackEnabled() bool {
+	return stackEnabled.Load()
+}
+
+// Stack 调用栈
+type Stack []uintptr
+
+// callers 记录调用栈，包内的调用在输出时再去掉
+func callers() Stack {
+	if !stackEnabled.Load() {
+		return nil
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	return append(Stack(nil), pcs[:n]...)
+}
+
+const pkgPrefix = "github.com/xtulnx/jkit-go/jerrno."
+
+// isInnerFrame 是否 jerrno 内部的调用（不含测试）
+func isInnerFrame(fn string) bool {
+	name, ok := strings.CutPrefix(fn, pkgPrefix)
+	return ok && !strings.HasPrefix(name, "Test")
+}
+
+// Frames 调用栈的每一帧，已去掉 jerrno 内部的调用
+func (s Stack) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	var frames []runtime.Frame
+	ff := runtime.CallersFrames(s)
+	inner := true
+	for {
+		f, more := ff.Next()
+		if inner && (isInnerFrame(f.Function) || f.File == "<autogenerated>") {
+			// 跳过开头的内部调用
+		} else {
+			inner = false
+			frames = append(frames, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// String 每帧两行：函数名、文件:行号
+func (s Stack) String() string {
+	var b strings.Builder
+	for _, f := range s.Frames() {
+		b.WriteString(f.Function)
+		b.WriteString("\n\t")
+		b.WriteString(f.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.Line))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// StackTrace 创建时的调用栈，没有开启时为空
+func (e error0) StackTrace() Stack {
+	return e.stack
+}
+
+// StackOf 沿错误链查找调用栈，返回最内层（最早创建）的
+func StackOf(err error) Stack {
+	var st Stack
+	for err != nil {
+		if ex, ok := err.(interface{ StackTrace() Stack }); ok {
+			if s1 := ex.StackTrace(); len(s1) > 0 {
+				st = s1
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return st
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+func (e error0) Format(s fmt.State, verb rune) {
+	formatError(s, verb, "error0", &e, nil, nil)
+}
+
+func (e error1) Format(s fmt.State, verb rune) {
+	formatError(s, verb, "error1", &e.error0, &e.Code, nil)
+}
+
+func (e error2) Format(s fmt.State, verb rune) {
+	formatError(s, verb, "error2", &e.error0, &e.Code, &e.HttpCode)
+}
+
+// formatError 实现 fmt.Formatter
+//
+//	%s %v: 错误信息
+//	%q: 带引号的错误信息
+//	%#v: 结构
+//	%+v: 信息、错误码、http码、原始错误链、调用栈
+func formatError(s fmt.State, verb rune, typ string, e *error0, code, httpCode *int) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			fmt.Fprintf(s, "&jerrno.%s{", typ)
+			if httpCode != nil {
+				fmt.Fprintf(s, "HttpCode:%d, ", *httpCode)
+			}
+			if code != nil {
+				fmt.Fprintf(s, "Code:%d, ", *code)
+			}
+			fmt.Fprintf(s, "Msg:%q, Err:%#v}", e.Msg, e.Err)
+			return
+		}
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.Error())
+			if code != nil {
+				fmt.Fprintf(s, "\ncode: %d", *code)
+			}
+			if httpCode != nil && *httpCode != 0 {
+				fmt.Fprintf(s, "\nhttp code: %d", *httpCode)
+			}
+			if len(e.Args) > 0 {
+				fmt.Fprintf(s, "\ntemplate: %s\nargs: %v", e.Msg, e.Args)
+			}
+			if len(e.Details) > 0 {
+				fmt.Fprintf(s, "\ndetails: %v", e.Details)
+			}
+			for _, f := range e.Fields {
+				fmt.Fprintf(s, "\nfield %s: %s", f.Field, f.Msg)
+			}
+			if len(e.stack) > 0 {
+				_, _ = io.WriteString(s, "\n"+strings.TrimSuffix(e.stack.String(), "\n"))
+			}
+			// 原始错误链，能自行格式化的（如 jerrno 的错误）交给它输出剩下的部分
+			for err := e.Err; err != nil; err = errors.Unwrap(err) {
+				if _, ok := err.(fmt.Formatter); ok {
+					fmt.Fprintf(s, "\ncaused by: %+v", err)
+					break
+				}
+				fmt.Fprintf(s, "\ncaused by: %s", err.Error())
+			}
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(jerrno.%s=%s)", verb, typ, e.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package jerrno
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	EnableStack(true)
+	defer EnableStack(false)
+
+	_, file, line, _ := runtime.Caller(0)
+	e1 := QueryNotFound.WithError(io.EOF) // 与 runtime.Caller 相邻，行号为 line+1
+	frames := StackOf(e1).Frames()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "jerrno.TestStack") {
+		t.Fatalf("StackOf() = %v", frames)
+	}
+	e2 := newErrWithHttpCode(404, 1, "外层").WithError(fmt.Errorf("包装: %w", e1))
+	if s := StackOf(e2).String(); !strings.Contains(s, fmt.Sprintf("%s:%d", file, line+1)) {
+		t.Errorf("StackOf() 应该返回最内层的调用栈:\n%s", s)
+	}
+
+	s := fmt.Sprintf("%+v", e2)
+	for _, want := range []string{"外层\ncode: 1\nhttp code: 404\n", "caused by: 包装: 记录并不存在\ncaused by: 记录并不存在\ncode: 550\n", "jerrno.TestStack\n\t", "caused by: EOF"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("%%+v 缺少 %q:\n%s", want, s)
+		}
+	}
+	t.Logf("%+v", e2)
+
+	for f, want := range map[string]string{
+		"%v":  "外层",
+		"%s":  "外层",
+		"%q":  `"外层"`,
+		"%#v": `&jerrno.error1{Code:550, Msg:"记录并不存在", Err:<nil>}`,
+	} {
+		e := e2
+		if f == "%#v" {
+			e = QueryNotFound
+		}
+		if got := fmt.Sprintf(f, e); got != want {
+			t.Errorf("Sprintf(%s) = %s, want %s", f, got, want)
+		}
+	}
+
+	EnableStack(false)
+	if s := StackOf(QueryNotFound.WithError(io.EOF)); len(s) != 0 {
+		t.Errorf("关闭后仍有调用栈: %v", s)
+	}
+}
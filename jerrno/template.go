@@ -0,0 +1,94 @@
+package jerrno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 信息模板，Msg 中的 {name} 在输出时用参数替换，原始模板与参数保留，便于本地化、记录日志
+//
+//	OrderNotFound = NewErrWithCode(10001, "订单 {id} 不存在")
+//	OrderNotFound.WithArgs("id", 123).Error() == "订单 123 不存在"
+
+// Template 原始的信息模板
+func (e error0) Template() string {
+	return e.Msg
+}
+
+// TemplateArgs 信息模板的参数
+func (e error0) TemplateArgs() map[string]any {
+	return e.Args
+}
+
+// withArgs 复制后再合并参数，不影响原来的错误
+//
+//	args: 单个 map[string]any、map[string]string，或 key、value 交替
+func (e error0) withArgs(args ...any) error0 {
+	m := make(map[string]any, len(e.Args)+len(args)/2)
+	for k, v := range e.Args {
+		m[k] = v
+	}
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case map[string]any:
+			for k, v1 := range v {
+				m[k] = v1
+			}
+			args = nil
+		case map[string]string:
+			for k, v1 := range v {
+				m[k] = v1
+			}
+			args = nil
+		}
+	}
+	for i := 0; i < len(args); i += 2 {
+		k := fmt.Sprint(args[i])
+		if i+1 < len(args) {
+			m[k] = args[i+1]
+		} else {
+			m[k] = nil
+		}
+	}
+	e.Args = m
+	return e
+}
+
+// ArgsOf 沿错误链查找第一个有参数的信息模板参数
+func ArgsOf(err error) map[string]any {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if ex, ok := err.(interface{ TemplateArgs() map[string]any }); ok && len(ex.TemplateArgs()) > 0 {
+			return ex.TemplateArgs()
+		}
+	}
+	return nil
+}
+
+// Render 用参数替换模板中的 {name}，没有对应参数的保持原样
+func Render(tpl string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(tpl, "{") {
+		return tpl
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(tpl, '{')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(tpl[i+1:], '}')
+		if j < 0 {
+			break
+		}
+		name := tpl[i+1 : i+1+j]
+		b.WriteString(tpl[:i])
+		if v, ok := args[name]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(tpl[i : i+2+j])
+		}
+		tpl = tpl[i+2+j:]
+	}
+	b.WriteString(tpl)
+	return b.String()
+}
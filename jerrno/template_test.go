@@ -0,0 +1,47 @@
+package jerrno
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	tpl := newErrWithCode(10001, "订单 {id} 不存在，{unknown}")
+	e1 := tpl.WithArgs("id", 123)
+	if s := e1.Error(); s != "订单 123 不存在，{unknown}" {
+		t.Errorf("Error() = %q", s)
+	}
+	if s := tpl.Error(); s != "订单 {id} 不存在，{unknown}" {
+		t.Errorf("模板被修改了: %q", s)
+	}
+
+	e2 := e1.WithError(io.EOF).WithArgs(map[string]any{"unknown": "请刷新"})
+	if s := e2.Error(); s != "订单 123 不存在，请刷新" || !errors.Is(e2, tpl) {
+		t.Errorf("Error() = %q", s)
+	}
+	ex := e2.(interface {
+		Template() string
+		TemplateArgs() map[string]any
+	})
+	if ex.Template() != "订单 {id} 不存在，{unknown}" || len(ex.TemplateArgs()) != 2 {
+		t.Errorf("Template() = %q, %v", ex.Template(), ex.TemplateArgs())
+	}
+
+	setMessage(t, "en", 10001, "Order {id} not found")
+	if s := Localize(fmt.Errorf("x: %w", e1), "en"); s != "Order 123 not found" {
+		t.Errorf("Localize() = %q", s)
+	}
+
+	for tpl, want := range map[string]string{
+		"":          "",
+		"{a}{b}":    "1{b}",
+		"x{a":       "x{a",
+		"{}{a}-{a}": "{}1-1",
+	} {
+		if s := Render(tpl, map[string]any{"a": 1}); s != want {
+			t.Errorf("Render(%q) = %q, want %q", tpl, s, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package jerrno
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// 把其他包的错误转换成 jerrno 的错误，如 gorm.ErrRecordNotFound => QueryNotFound。
+//
+// 默认只处理 context 的超时，gorm、validator 的转换见 jerrno/translator，
+// 数据库驱动的转换在 jgorm/driver_mysql、jgorm/driver_sqlite 中注册。
+
+// Translator 错误转换，不能处理时返回 nil
+type Translator func(err error) ErrEx
+
+var (
+	translatorsMu sync.RWMutex
+	translators   = []Translator{TranslateContext}
+)
+
+// RegTranslator 注册错误转换，后注册的优先
+func RegTranslator(t Translator) {
+	translatorsMu.Lock()
+	translators = append(translators, t)
+	translatorsMu.Unlock()
+}
+
+// Translate 转换错误。错误链中已经有错误码的原样返回；
+// 否则依次尝试已注册的转换，转换后的错误通过 WithError 保留原始错误
+func Translate(err error) error {
+	if err == nil || CoderOf(err) != nil {
+		return err
+	}
+	// 只追加不修改，取出当前的列表后不再持有锁，转换函数中也可以注册
+	translatorsMu.RLock()
+	ts := translators
+	translatorsMu.RUnlock()
+	for i := len(ts) - 1; i >= 0; i-- {
+		if ex := ts[i](err); ex != nil {
+			return ex.WithError(err)
+		}
+	}
+	return err
+}
+
+// TranslateContext context 超时 => GatewayTimeout
+func TranslateContext(err error) ErrEx {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return GatewayTimeout
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package jerrno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTranslate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if err := Translate(fmt.Errorf("查询: %w", ctx.Err())); !errors.Is(err, GatewayTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Translate(DeadlineExceeded) = %v", err)
+	}
+	if err := Translate(io.EOF); err != io.EOF {
+		t.Errorf("Translate(io.EOF) = %v", err)
+	}
+	e1 := QueryFailed.WithError(context.DeadlineExceeded)
+	if err := Translate(e1); err != e1 {
+		t.Errorf("已有错误码的应原样返回: %v", err)
+	}
+
+	keepTranslators(t)
+	RegTranslator(func(err error) ErrEx {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return InternalServerError
+		}
+		return nil
+	})
+	if err := Translate(context.DeadlineExceeded); !errors.Is(err, InternalServerError) {
+		t.Errorf("后注册的应优先: %v", err)
+	}
+}
+
+// keepTranslators 测试结束后恢复已注册的转换
+func keepTranslators(t *testing.T) {
+	translatorsMu.RLock()
+	saved := slices.Clip(translators)
+	translatorsMu.RUnlock()
+	t.Cleanup(func() {
+		translatorsMu.Lock()
+		translators = saved
+		translatorsMu.Unlock()
+	})
+}
@@ -0,0 +1,40 @@
+package translator
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"gorm.io/gorm"
+)
+
+// 常用的错误转换，引用即注册
+//
+//	import _ "github.com/xtulnx/jkit-go/jerrno/translator"
+//
+// 数据库驱动的重复键错误，在 jgorm/driver_mysql、jgorm/driver_sqlite 中注册
+
+func init() {
+	jerrno.RegTranslator(Gorm)
+	jerrno.RegTranslator(Validation)
+}
+
+// Gorm 记录不存在 => QueryNotFound，重复键（开启 TranslateError 时） => Conflict
+func Gorm(err error) jerrno.ErrEx {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return jerrno.QueryNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return jerrno.Conflict
+	}
+	return nil
+}
+
+// Validation 参数校验失败 => BadRequest
+func Validation(err error) jerrno.ErrEx {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return jerrno.BadRequest
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"gorm.io/gorm"
+)
+
+func TestTranslate(t *testing.T) {
+	type req struct {
+		Mobile string `validate:"required"`
+	}
+	ve := validator.New().Struct(req{})
+
+	for _, v := range []struct {
+		err  error
+		want error
+	}{
+		{fmt.Errorf("查询订单: %w", gorm.ErrRecordNotFound), jerrno.QueryNotFound},
+		{gorm.ErrDuplicatedKey, jerrno.Conflict},
+		{ve, jerrno.BadRequest},
+	} {
+		if err := jerrno.Translate(v.err); !errors.Is(err, v.want) || errors.Unwrap(err) == nil {
+			t.Errorf("Translate(%v) = %v, want %v", v.err, err, v.want)
+		}
+	}
+}
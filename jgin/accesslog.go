@@ -0,0 +1,86 @@
+package jgin
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AccessLogConfig 访问日志的配置
+type AccessLogConfig struct {
+	SkipPaths   []string `mapstructure:"skipPaths,omitempty" json:"skipPaths,omitempty" yaml:"skipPaths,omitempty" toml:"skipPaths,omitempty"`         // 不记录的路径，以 * 结尾时按前缀匹配，如 /static/*
+	SampleEvery int      `mapstructure:"sampleEvery,omitempty" json:"sampleEvery,omitempty" yaml:"sampleEvery,omitempty" toml:"sampleEvery,omitempty"` // 成功的请求每 N 个记录一个，0、1 表示全部记录；失败、慢请求总是记录
+	SlowMs      int      `mapstructure:"slowMs,omitempty" json:"slowMs,omitempty" yaml:"slowMs,omitempty" toml:"slowMs,omitempty"`                     // 慢请求阈值（毫秒），超过时以 warn 级别记录，0 表示不检查
+}
+
+// AccessLog 访问日志中间件：方法、路径、路由、http 状态码、业务码、耗时、客户端 ip、响应大小、请求 ID。
+// 5xx 以 error 级别记录，慢请求以 warn 级别记录，其他为 info
+//
+//	r.Use(jgin.RequestID(), jgin.AccessLog(jlog.Zap.New(conf), jgin.AccessLogConfig{SkipPaths: []string{"/health"}}))
+func AccessLog(l *zap.Logger, conf AccessLogConfig) gin.HandlerFunc {
+	skip := make(map[string]struct{})
+	var skipPrefix []string
+	for _, p := range conf.SkipPaths {
+		if s, ok := strings.CutSuffix(p, "*"); ok {
+			skipPrefix = append(skipPrefix, s)
+		} else {
+			skip[p] = struct{}{}
+		}
+	}
+	slow := time.Duration(conf.SlowMs) * time.Millisecond
+	var n atomic.Uint64
+
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if _, ok := skip[path]; ok {
+			c.Next()
+			return
+		}
+		for _, p := range skipPrefix {
+			if strings.HasPrefix(path, p) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		status := c.Writer.Status()
+		code, hasCode := ResponseCode(c)
+		level := zapcore.InfoLevel
+		switch {
+		case status >= 500:
+			level = zapcore.ErrorLevel
+		case slow > 0 && latency >= slow:
+			level = zapcore.WarnLevel
+		case status < 400 && (!hasCode || code == SUCCESS) && conf.SampleEvery > 1:
+			if n.Add(1)%uint64(conf.SampleEvery) != 1 {
+				return
+			}
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Duration("latency", latency),
+			zap.String("ip", c.ClientIP()),
+			zap.Int("bytes", max(c.Writer.Size(), 0)),
+		}
+		if hasCode {
+			fields = append(fields, zap.Int("code", code))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+		jlog.ForContext(c.Request.Context(), l).Log(level, "访问", fields...)
+	}
+}
@@ -0,0 +1,65 @@
+package jgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jlog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	r := gin.New()
+	r.Use(RequestID(), AccessLog(zap.New(core), AccessLogConfig{
+		SkipPaths:   []string{"/health", "/static/*"},
+		SampleEvery: 2,
+		SlowMs:      20,
+	}))
+	r.GET("/health", Ok)
+	r.GET("/static/*path", Ok)
+	r.GET("/ok", Ok)
+	r.GET("/order/:id", func(c *gin.Context) {
+		ResultErr(nil, jerrno.QueryNotFound, c)
+	})
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(25 * time.Millisecond)
+		Ok(c)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+
+	for _, path := range []string{"/health", "/static/a.js", "/ok", "/ok", "/ok", "/order/1", "/slow", "/panic"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := logs.AllUntimed()
+	want := []struct {
+		path  string
+		level zapcore.Level
+	}{
+		{"/ok", zapcore.InfoLevel},
+		{"/ok", zapcore.InfoLevel},
+		{"/order/1", zapcore.InfoLevel},
+		{"/slow", zapcore.WarnLevel},
+		{"/panic", zapcore.ErrorLevel},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("日志数量 %d, want %d: %v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if m := entries[i].ContextMap(); m["path"] != w.path || entries[i].Level != w.level || m[jlog.KeyRequestID] == "" {
+			t.Errorf("[%d] = %v %v, want %s %v", i, entries[i].Level, m, w.path, w.level)
+		}
+	}
+	if m := entries[2].ContextMap(); m["code"] != int64(550) || m["route"] != "/order/:id" || m["bytes"].(int64) <= 0 {
+		t.Errorf("/order/1 = %v", m)
+	}
+}
@@ -0,0 +1,190 @@
+package jgin
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// 登录认证与权限检查
+//
+//	j, err := jgin.NewJWT(conf)
+//	api := r.Group("/api", j.Auth())
+//	api.DELETE("/order/:id", jgin.RequirePerms("order:delete"), h)
+//
+// 请求结构实现 SetUser（如嵌入 ReqWithUser）时，GinMustBind 会注入当前用户
+
+// User 当前登录的用户
+type User struct {
+	ID     string    `json:"id"`
+	Name   string    `json:"name,omitempty"`
+	Roles  []string  `json:"roles,omitempty"`
+	Perms  []string  `json:"perms,omitempty"`
+	Claims JWTClaims `json:"-"` // token 中的全部数据
+}
+
+// HasRole 是否有任意一个角色
+func (u *User) HasRole(roles ...string) bool {
+	return u != nil && containsAny(u.Roles, roles)
+}
+
+// HasPerm 是否有全部权限，权限以 * 结尾时按前缀匹配，如 order:* 包含 order:delete
+func (u *User) HasPerm(perms ...string) bool {
+	if u == nil {
+		return false
+	}
+	for _, p := range perms {
+		if !matchPerm(u.Perms, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(ss, targets []string) bool {
+	for _, s := range ss {
+		for _, t := range targets {
+			if s == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchPerm(perms []string, p string) bool {
+	for _, v := range perms {
+		if v == p || v == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(v, "*"); ok && strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+const ctxKeyUser = "jgin.user"
+
+// SetUser 设置当前请求的用户，同时设置 SetUserID，u 为 nil 时忽略
+func SetUser(c *gin.Context, u *User) {
+	if u == nil {
+		return
+	}
+	c.Set(ctxKeyUser, u)
+	SetUserID(c, u.ID)
+}
+
+// UserOf 当前请求的用户，未登录时为 nil
+func UserOf(c *gin.Context) *User {
+	if v, ok := c.Get(ctxKeyUser); ok {
+		u, _ := v.(*User)
+		return u
+	}
+	return nil
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// SetClaimsMapper 自定义 token 数据到用户的转换，默认见 defaultUser
+func (j *JWT) SetClaimsMapper(fn func(claims JWTClaims) (*User, error)) *JWT {
+	j.mapper = fn
+	return j
+}
+
+// defaultUser sub 为用户 ID，角色、权限、用户名按配置的字段读取
+func (j *JWT) defaultUser(claims JWTClaims) (*User, error) {
+	u := &User{
+		ID:     claims.Subject(),
+		Name:   claims.String(j.conf.NameClaim),
+		Roles:  claims.Strings(j.conf.RolesClaim),
+		Perms:  claims.Strings(j.conf.PermsClaim),
+		Claims: claims,
+	}
+	if u.ID == "" {
+		return nil, ErrTokenSubject
+	}
+	return u, nil
+}
+
+// TokenOf 按配置从请求头、查询参数、cookie 读取 token
+func (j *JWT) TokenOf(c *gin.Context) string {
+	if s := strings.TrimSpace(c.GetHeader(j.conf.Header)); s != "" {
+		if len(s) > 7 && strings.EqualFold(s[:7], "Bearer ") {
+			return strings.TrimSpace(s[7:])
+		}
+		return s
+	}
+	if j.conf.Query != "" {
+		if s := c.Query(j.conf.Query); s != "" {
+			return s
+		}
+	}
+	if j.conf.Cookie != "" {
+		if s, err := c.Cookie(j.conf.Cookie); err == nil && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// Auth 认证中间件，校验 token 并设置当前用户；失败时返回 jerrno.Unauthorized
+func (j *JWT) Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := j.TokenOf(c)
+		if token == "" {
+			if !j.conf.Optional {
+				abortErr(c, jerrno.Unauthorized.WithError(ErrTokenMissing))
+			}
+			return
+		}
+		claims, err := j.Verify(token)
+		if err != nil {
+			abortErr(c, jerrno.Unauthorized.WithError(err))
+			return
+		}
+		mapper := j.mapper
+		if mapper == nil {
+			mapper = j.defaultUser
+		}
+		u, err := mapper(claims)
+		if err != nil {
+			abortErr(c, jerrno.Unauthorized.WithError(err))
+			return
+		}
+		if u == nil {
+			abortErr(c, jerrno.Unauthorized.WithError(ErrTokenSubject))
+			return
+		}
+		SetUser(c, u)
+	}
+}
+
+// RequireRoles 需要任意一个角色，未登录返回 jerrno.Unauthorized，没有角色返回 jerrno.Forbidden
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return requireUser(func(u *User) bool { return u.HasRole(roles...) })
+}
+
+// RequirePerms 需要全部权限，见 User.HasPerm
+func RequirePerms(perms ...string) gin.HandlerFunc {
+	return requireUser(func(u *User) bool { return u.HasPerm(perms...) })
+}
+
+func requireUser(check func(u *User) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u := UserOf(c)
+		if u == nil {
+			abortErr(c, jerrno.Unauthorized)
+			return
+		}
+		if !check(u) {
+			abortErr(c, jerrno.Forbidden)
+		}
+	}
+}
+
+func abortErr(c *gin.Context, e error) {
+	c.Abort()
+	ResultErr(nil, e, c)
+}
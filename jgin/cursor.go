@@ -0,0 +1,206 @@
+package jgin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jgorm"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+// 游标分页：游标是签名后的最后一行（向前翻页时为第一行）的排序值，客户端原样传回
+//
+//	cols := []jgorm.KeysetColumn{{Column: q.Order.CreatedAt, Desc: true}, {Column: q.Order.ID, Desc: true}}
+//	res, err := jgin.CursorPaginate[model.Order](c.Request.Context(), db.Where("status = ?", 1), req.CursorReq, cols...)
+//	jgin.ResultErr(res, err, c)
+
+// CursorReq 游标分页请求
+type CursorReq struct {
+	Cursor string `form:"cursor" json:"cursor,omitempty" query:"cursor"`        // 上次返回的 next 或 prev，为空时从头开始
+	Size   int    `form:"size" json:"size,omitempty" query:"size" example:"10"` // 分页大小
+}
+
+// CursorResp 游标分页结果，没有下一页（上一页）时 Next（Prev）为空
+type CursorResp struct {
+	Next string `json:"next,omitempty"` // 下一页的游标
+	Prev string `json:"prev,omitempty"` // 上一页的游标
+	Size int    `json:"size" example:"10"`
+}
+
+// CursorResult 游标分页结果，可以直接用于 OkWithData
+type CursorResult[T any] struct {
+	CursorResp
+	List []T `json:"list"`
+}
+
+// CursorConfig 游标的签名
+type CursorConfig struct {
+	Secret string `mapstructure:"secret,omitempty" json:"secret,omitempty" yaml:"secret,omitempty" toml:"secret,omitempty"` // 签名密钥，为空时随机生成，重启或多实例部署时游标失效
+}
+
+var cursorSecret = randomSecret()
+
+// SetCursorConfig 设置游标的签名
+func SetCursorConfig(conf CursorConfig) {
+	if conf.Secret == "" {
+		cursorSecret = randomSecret()
+		return
+	}
+	cursorSecret = []byte(conf.Secret)
+}
+
+func randomSecret() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// cursorToken 游标的内容
+type cursorToken struct {
+	Values   []json.RawMessage `json:"k"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+// cursorSign 签名包含排序列，排序列不同的游标不能互用
+func cursorSign(payload []byte, cols []jgorm.KeysetColumn) []byte {
+	m := hmac.New(sha256.New, cursorSecret)
+	m.Write(payload)
+	for _, c := range cols {
+		m.Write([]byte{0})
+		if c.Desc {
+			m.Write([]byte{'-'})
+		}
+		m.Write([]byte(c.Name()))
+	}
+	return m.Sum(nil)
+}
+
+// encodeCursor base64(json).base64(签名)
+func encodeCursor(values []any, backward bool, cols []jgorm.KeysetColumn) (string, error) {
+	t := cursorToken{Values: make([]json.RawMessage, len(values)), Backward: backward}
+	for i, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		t.Values[i] = b
+	}
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(cursorSign(payload, cols)), nil
+}
+
+func decodeCursor(s string, cols []jgorm.KeysetColumn) (t cursorToken, ok bool) {
+	p, sig, found := strings.Cut(s, ".")
+	if !found {
+		return t, false
+	}
+	enc := base64.RawURLEncoding
+	payload, err1 := enc.DecodeString(p)
+	sign, err2 := enc.DecodeString(sig)
+	if err1 != nil || err2 != nil || !hmac.Equal(sign, cursorSign(payload, cols)) {
+		return t, false
+	}
+	if json.Unmarshal(payload, &t) != nil || len(t.Values) != len(cols) {
+		return t, false
+	}
+	return t, true
+}
+
+// CursorPaginate 游标分页查询 db，没有指定 Model、Table 时按 T 查询。
+// cols 为排序列，组合需要唯一，通常以主键结尾；db 中不要再指定排序
+func CursorPaginate[T any](ctx context.Context, db *gorm.DB, req CursorReq, cols ...jgorm.KeysetColumn) (*CursorResult[T], error) {
+	if len(cols) == 0 {
+		return nil, errors.New("jgin: 游标分页需要指定排序列")
+	}
+	size := req.Size
+	if size <= 0 {
+		size = pageConfig.DefaultSize
+	}
+	if pageConfig.MaxSize > 0 && size > pageConfig.MaxSize {
+		size = pageConfig.MaxSize
+	}
+
+	db = db.WithContext(ctx)
+	if db.Statement.Model == nil && db.Statement.Table == "" && db.Statement.TableExpr == nil {
+		db = db.Model(new(T))
+	}
+	fields, err := jgorm.KeysetFields(db, new(T), cols)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []any
+	var backward bool
+	if req.Cursor != "" {
+		t, ok := decodeCursor(req.Cursor, cols)
+		if !ok {
+			return nil, jerrno.BadRequest.WithFieldError("cursor", "游标无效")
+		}
+		backward = t.Backward
+		values = make([]any, len(fields))
+		for i, f := range fields {
+			v := reflect.New(f.FieldType)
+			if json.Unmarshal(t.Values[i], v.Interface()) != nil {
+				return nil, jerrno.BadRequest.WithFieldError("cursor", "游标无效")
+			}
+			values[i] = v.Elem().Interface()
+		}
+	}
+
+	res := &CursorResult[T]{CursorResp: CursorResp{Size: size}, List: []T{}}
+	q, err := jgorm.KeysetDB(db, cols, values, backward)
+	if err != nil {
+		return nil, err
+	}
+	if err = q.Limit(size + 1).Find(&res.List).Error; err != nil {
+		return nil, err
+	}
+	more := len(res.List) > size
+	if more {
+		res.List = res.List[:size]
+	}
+	if backward {
+		slices.Reverse(res.List)
+	}
+	if len(res.List) == 0 {
+		return res, nil
+	}
+
+	// 向后翻页时，有多出的一行说明还有下一页，带了游标说明有上一页；向前翻页相反
+	hasNext, hasPrev := more, req.Cursor != ""
+	if backward {
+		hasNext, hasPrev = hasPrev, hasNext
+	}
+	if hasNext {
+		last := jgorm.KeysetValues(db, fields, &res.List[len(res.List)-1])
+		if res.Next, err = encodeCursor(last, false, cols); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		first := jgorm.KeysetValues(db, fields, &res.List[0])
+		if res.Prev, err = encodeCursor(first, true, cols); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// CursorPaginateDao 游标分页查询 gen 的 dao，见 CursorPaginate
+func CursorPaginateDao[T any](ctx context.Context, dao gen.Dao, req CursorReq, cols ...jgorm.KeysetColumn) (*CursorResult[T], error) {
+	return CursorPaginate[T](ctx, dao.(*gen.DO).UnderlyingDB(), req, cols...)
+}
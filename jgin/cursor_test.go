@@ -0,0 +1,110 @@
+package jgin
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jgorm"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+type tCursorItem struct {
+	ID    uint `gorm:"primarykey"`
+	Score int
+	At    time.Time
+}
+
+func TestCursorPaginate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:cursor?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&tCursorItem{}); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.Local)
+	var items []tCursorItem
+	for i := 1; i <= 23; i++ {
+		items = append(items, tCursorItem{ID: uint(i), Score: i % 4, At: base.Add(time.Duration(i%3) * time.Hour)})
+	}
+	db.Create(&items)
+
+	// 分数倒序、时间正序、id 倒序
+	cols := []jgorm.KeysetColumn{{Column: "score", Desc: true}, {Column: field.NewTime("", "at")}, {Column: field.NewUint("", "id"), Desc: true}}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		return a.ID > b.ID
+	})
+	var want []uint
+	for _, v := range items {
+		want = append(want, v.ID)
+	}
+
+	ctx := context.Background()
+	var got []uint
+	var pages []*CursorResult[tCursorItem]
+	req := CursorReq{Size: 5}
+	for {
+		res, err := CursorPaginate[tCursorItem](ctx, db, req, cols...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (len(pages) == 0) != (res.Prev == "") {
+			t.Errorf("第 %d 页 Prev = %q", len(pages)+1, res.Prev)
+		}
+		pages = append(pages, res)
+		for _, v := range res.List {
+			got = append(got, v.ID)
+		}
+		if res.Next == "" {
+			break
+		}
+		req.Cursor = res.Next
+	}
+	if len(pages) != 5 || !slices.Equal(got, want) {
+		t.Fatalf("向后翻页 = %v, 期望 %v", got, want)
+	}
+
+	// 从最后一页向前翻
+	dao := &gen.DO{}
+	dao.UseDB(db.Model(&tCursorItem{}))
+	for i := len(pages) - 1; i > 0; i-- {
+		res, err := CursorPaginateDao[tCursorItem](ctx, dao, CursorReq{Cursor: pages[i].Prev, Size: 5}, cols...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.EqualFunc(res.List, pages[i-1].List, func(a, b tCursorItem) bool { return a.ID == b.ID }) ||
+			res.Next == "" || (i == 1) != (res.Prev == "") {
+			t.Errorf("向前翻到第 %d 页 = %+v", i, res)
+		}
+	}
+
+	for _, cursor := range []string{"abc", pages[1].Next[:len(pages[1].Next)-2] + "AA"} {
+		if _, err = CursorPaginate[tCursorItem](ctx, db, CursorReq{Cursor: cursor}, cols...); !errors.Is(err, jerrno.BadRequest) {
+			t.Errorf("游标 %q = %v", cursor, err)
+		}
+	}
+	if _, err = CursorPaginate[tCursorItem](ctx, db, CursorReq{Cursor: pages[1].Next}, cols[1:]...); !errors.Is(err, jerrno.BadRequest) {
+		t.Errorf("排序列不同 = %v", err)
+	}
+	if _, err = CursorPaginate[tCursorItem](ctx, db, CursorReq{}); err == nil {
+		t.Error("没有排序列时没有报错")
+	}
+	if _, err = jgorm.KeysetCond(cols, []any{1}, false); err == nil {
+		t.Error("KeysetCond 值的个数不同时没有报错")
+	}
+}
@@ -0,0 +1,131 @@
+package jgin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// 响应的封装格式，Result、ResultErr 以及 Ok*、Fail* 都经 Envelope 输出。
+// 默认为 Response 的 {code,data,msg}，可以全局替换，也可以按路由组指定：
+//
+//	admin := r.Group("/admin", jgin.UseEnvelope(jgin.NewEnvelope(jgin.EnvelopeConfig{
+//		SuccessKey: "success", CodeKey: "errorCode", MsgKey: "errorMessage", DataKey: "result",
+//	})))
+
+// ResponseInfo 一次响应的内容
+type ResponseInfo struct {
+	Success  bool                // 是否成功，即业务码为 SUCCESS
+	Code     int                 // 业务码
+	HttpCode int                 // 错误指定的 http 状态码，没有时为 0，由 Envelope 决定
+	Msg      string              // 信息，错误信息已按请求的语言本地化
+	Data     any                 // 数据
+	Details  map[string]any      // 错误的详细信息
+	Fields   []jerrno.FieldError // 字段错误
+	Err      error               // ResultErr 的错误（已经过 jerrno.Translate 转换），成功时为空
+}
+
+// Envelope 响应的封装格式
+type Envelope interface {
+	Render(c *gin.Context, r *ResponseInfo)
+}
+
+// EnvelopeFunc 用函数实现 Envelope
+type EnvelopeFunc func(c *gin.Context, r *ResponseInfo)
+
+func (f EnvelopeFunc) Render(c *gin.Context, r *ResponseInfo) {
+	f(c, r)
+}
+
+var (
+	DefaultEnvelope Envelope = EnvelopeFunc(renderDefault) // 使用 Response
+	ProblemEnvelope Envelope = EnvelopeFunc(renderProblem) // 错误使用 RFC 7807 格式，成功时同 DefaultEnvelope，见 Problem
+)
+
+const ctxKeyEnvelope = "jgin.envelope"
+
+var envelope = DefaultEnvelope
+
+// SetEnvelope 设置全局的响应格式
+func SetEnvelope(e Envelope) {
+	if e == nil {
+		e = DefaultEnvelope
+	}
+	envelope = e
+}
+
+// UseEnvelope 中间件，设置路由组的响应格式
+func UseEnvelope(e Envelope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ctxKeyEnvelope, e)
+	}
+}
+
+// EnvelopeOf 请求使用的响应格式：路由组的 > RenderProblem 模式 > 全局的
+func EnvelopeOf(c *gin.Context) Envelope {
+	if v, ok := c.Get(ctxKeyEnvelope); ok {
+		if e, ok := v.(Envelope); ok {
+			return e
+		}
+	}
+	if RenderModeOf(c) == RenderProblem {
+		return ProblemEnvelope
+	}
+	return envelope
+}
+
+func renderDefault(c *gin.Context, r *ResponseInfo) {
+	c.JSON(r.Status(http.StatusOK), Response{Code: r.Code, Data: r.Data, Msg: r.Msg, Details: r.Details, Fields: r.Fields})
+}
+
+// Status 错误指定的 http 状态码，没有时使用 def
+func (r *ResponseInfo) Status(def int) int {
+	if r.HttpCode != 0 {
+		return r.HttpCode
+	}
+	return def
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// EnvelopeConfig 按指定的字段名输出响应，字段名为空时不输出该字段
+type EnvelopeConfig struct {
+	SuccessKey  string `mapstructure:"successKey,omitempty" json:"successKey,omitempty" yaml:"successKey,omitempty" toml:"successKey,omitempty"`     // 是否成功，true、false
+	CodeKey     string `mapstructure:"codeKey,omitempty" json:"codeKey,omitempty" yaml:"codeKey,omitempty" toml:"codeKey,omitempty"`                 // 业务码
+	MsgKey      string `mapstructure:"msgKey,omitempty" json:"msgKey,omitempty" yaml:"msgKey,omitempty" toml:"msgKey,omitempty"`                     // 信息
+	DataKey     string `mapstructure:"dataKey,omitempty" json:"dataKey,omitempty" yaml:"dataKey,omitempty" toml:"dataKey,omitempty"`                 // 数据，为空时不输出
+	DetailsKey  string `mapstructure:"detailsKey,omitempty" json:"detailsKey,omitempty" yaml:"detailsKey,omitempty" toml:"detailsKey,omitempty"`     // 错误的详细信息，为空时不输出
+	FieldsKey   string `mapstructure:"fieldsKey,omitempty" json:"fieldsKey,omitempty" yaml:"fieldsKey,omitempty" toml:"fieldsKey,omitempty"`         // 字段错误，为空时不输出
+	SuccessCode int    `mapstructure:"successCode,omitempty" json:"successCode,omitempty" yaml:"successCode,omitempty" toml:"successCode,omitempty"` // 成功时输出的业务码，默认 0
+}
+
+// NewEnvelope 按配置的字段名输出响应，如 {success, errorCode, errorMessage, result}
+func NewEnvelope(conf EnvelopeConfig) Envelope {
+	return EnvelopeFunc(func(c *gin.Context, r *ResponseInfo) {
+		m := make(map[string]any, 6)
+		if conf.SuccessKey != "" {
+			m[conf.SuccessKey] = r.Success
+		}
+		if conf.CodeKey != "" {
+			if r.Success {
+				m[conf.CodeKey] = conf.SuccessCode
+			} else {
+				m[conf.CodeKey] = r.Code
+			}
+		}
+		if conf.MsgKey != "" {
+			m[conf.MsgKey] = r.Msg
+		}
+		if conf.DataKey != "" && r.Data != nil {
+			m[conf.DataKey] = r.Data
+		}
+		if conf.DetailsKey != "" && len(r.Details) > 0 {
+			m[conf.DetailsKey] = r.Details
+		}
+		if conf.FieldsKey != "" && len(r.Fields) > 0 {
+			m[conf.FieldsKey] = r.Fields
+		}
+		c.JSON(r.Status(http.StatusOK), m)
+	})
+}
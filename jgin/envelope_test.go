@@ -0,0 +1,69 @@
+package jgin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+func TestEnvelope(t *testing.T) {
+	partner := NewEnvelope(EnvelopeConfig{SuccessKey: "success", CodeKey: "errorCode", MsgKey: "errorMessage", DataKey: "result", FieldsKey: "errors", SuccessCode: 200})
+
+	r := gin.New()
+	for _, g := range []*gin.RouterGroup{
+		r.Group("/api"),
+		r.Group("/partner", UseEnvelope(partner)),
+		r.Group("/problem", UseRenderMode(RenderProblem)),
+		r.Group("/both", UseRenderMode(RenderProblem), UseEnvelope(partner)),
+	} {
+		g.GET("/ok", func(c *gin.Context) { OkWithData([]int{1}, c) })
+		g.GET("/fail", func(c *gin.Context) { FailWithMessage("失败", c) })
+		g.GET("/err", func(c *gin.Context) {
+			ResultErr(nil, jerrno.Unauthorized.WithFieldError("token", "已过期"), c)
+		})
+	}
+
+	for _, v := range []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/api/ok", 200, `{"code":0,"data":[1],"msg":"查询成功"}`},
+		{"/api/fail", 200, `{"code":7,"msg":"失败"}`},
+		{"/api/err", 200, `{"code":401,"msg":"请先登录","fields":[{"field":"token","msg":"已过期"}]}`},
+		{"/partner/ok", 200, `{"success":true,"errorCode":200,"errorMessage":"查询成功","result":[1]}`},
+		{"/partner/fail", 200, `{"success":false,"errorCode":7,"errorMessage":"失败"}`},
+		{"/partner/err", 200, `{"success":false,"errorCode":401,"errorMessage":"请先登录","errors":[{"field":"token","msg":"已过期"}]}`},
+		{"/problem/ok", 200, `{"code":0,"data":[1],"msg":"查询成功"}`},
+		{"/problem/err", 401, `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"请先登录","instance":"/problem/err","code":401,"fields":[{"field":"token","msg":"已过期"}]}`},
+		{"/both/err", 200, `{"success":false,"errorCode":401,"errorMessage":"请先登录","errors":[{"field":"token","msg":"已过期"}]}`},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, v.path, nil))
+		var got, want any
+		_ = json.Unmarshal(w.Body.Bytes(), &got)
+		_ = json.Unmarshal([]byte(v.want), &want)
+		if w.Code != v.status || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %d %s", v.path, w.Code, w.Body.String())
+		}
+	}
+
+	// 全局替换，错误指定的 http 状态码仍然有效
+	SetEnvelope(EnvelopeFunc(func(c *gin.Context, r *ResponseInfo) {
+		c.String(r.Status(http.StatusOK), "%v:%d:%s", r.Success, r.Code, r.Msg)
+	}))
+	defer SetEnvelope(nil)
+	r.GET("/str", func(c *gin.Context) {
+		ResultErr(nil, jerrno.FromCode(9404, 404, "不存在"), c)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/str", nil))
+	if w.Code != 404 || w.Body.String() != "false:9404:不存在" {
+		t.Errorf("SetEnvelope() = %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,23 @@
+package jgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jlog"
+	"go.uber.org/zap"
+)
+
+// ErrorLogger 记录 ResultErr 返回的错误，日志级别按错误的严重程度，见 jlog.LogErr。
+// 日志带上请求 ID，见 RequestID
+func ErrorLogger(l *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if err := ResponseError(c); err != nil {
+			jlog.LogErr(jlog.ForContext(c.Request.Context(), l), "请求出错", err,
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.Int("status", c.Writer.Status()),
+			)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package jgin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// Handle 把业务函数转为 gin 的处理函数：用 GinMustBind 解析请求（包括 SetNow、SetIP、SetCtx 等），
+// 调用 fn，再用 ResultErr 返回结果。解析请求失败且不是 jerrno 错误时，返回 jerrno.BadRequest。
+// 带 uri、header 标签的字段先分别从路由参数、请求头解析
+//
+//	r.POST("/order", jgin.Handle(svc.CreateOrder))
+//
+//	func (s *Service) CreateOrder(ctx context.Context, req *CreateOrderReq) (*Order, error)
+func Handle[Req, Resp any](fn func(ctx context.Context, req *Req) (Resp, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := new(Req)
+		if err := bindUriHeader(c, req); err != nil {
+			ResultErr(nil, bindError(err), c)
+			return
+		}
+		if err := GinMustBind(c, req); err != nil {
+			ResultErr(nil, bindError(err), c)
+			return
+		}
+		resp, err := fn(c.Request.Context(), req)
+		if err != nil {
+			ResultErr(nil, err, c)
+			return
+		}
+		ResultErr(resp, nil, c)
+	}
+}
+
+// bindError 解析请求的错误，没有错误码的视为参数错误
+func bindError(err error) error {
+	err = jerrno.Translate(err)
+	if jerrno.CoderOf(err) == nil {
+		return jerrno.BadRequest.WithError(err)
+	}
+	return err
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+type tBindTags struct {
+	uri, header bool
+}
+
+var bindTagsCache sync.Map // reflect.Type -> tBindTags
+
+// bindTagsOf 结构体（包括匿名嵌入的）是否有 uri、header 标签
+func bindTagsOf(t reflect.Type) tBindTags {
+	if v, ok := bindTagsCache.Load(t); ok {
+		return v.(tBindTags)
+	}
+	var tags tBindTags
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if _, ok := f.Tag.Lookup("uri"); ok {
+				tags.uri = true
+			}
+			if _, ok := f.Tag.Lookup("header"); ok {
+				tags.header = true
+			}
+			if f.Anonymous {
+				walk(f.Type)
+			}
+		}
+	}
+	walk(t)
+	bindTagsCache.Store(t, tags)
+	return tags
+}
+
+// bindUriHeader 解析路由参数、请求头，校验留给之后的整体解析
+func bindUriHeader(c *gin.Context, obj any) error {
+	tags := bindTagsOf(reflect.TypeOf(obj))
+	var err error
+	if tags.uri && len(c.Params) > 0 {
+		m := make(map[string][]string, len(c.Params))
+		for _, v := range c.Params {
+			m[v.Key] = []string{v.Value}
+		}
+		err = binding.Uri.BindUri(m, obj)
+	}
+	if tags.header && skipValidation(err) == nil {
+		err = binding.Header.Bind(c.Request, obj)
+	}
+	return skipValidation(err)
+}
+
+func skipValidation(err error) error {
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package jgin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+type tHandleReq struct {
+	ReqWithIP
+	Id int `json:"id" form:"id" binding:"required"`
+}
+
+func TestHandle(t *testing.T) {
+	h := Handle(func(ctx context.Context, req *tHandleReq) (map[string]any, error) {
+		if req.Id == 404 {
+			return nil, jerrno.QueryNotFound
+		}
+		return map[string]any{"id": req.Id, "ip": req.GetIP()}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "10.0.0.1:1234"
+	_, resp := doRequest(t, h, req)
+	if data, _ := resp.Data.(map[string]any); resp.Code != SUCCESS || data["id"] != 1.0 || data["ip"] != "10.0.0.1" {
+		t.Errorf("Handle() = %+v", resp)
+	}
+
+	_, resp = doRequest(t, h, httptest.NewRequest(http.MethodGet, "/?id=404", nil))
+	if resp.Code != 550 || resp.Data != nil {
+		t.Errorf("Handle() 业务错误 = %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, resp = doRequest(t, h, req); resp.Code != 400 {
+		t.Errorf("Handle() 解析失败 = %+v", resp)
+	}
+	if _, resp = doRequest(t, h, httptest.NewRequest(http.MethodGet, "/", nil)); resp.Code != 400 {
+		t.Errorf("Handle() 校验失败 = %+v", resp)
+	}
+}
+
+type tHandleUriReq struct {
+	Id    uint   `uri:"id" json:"-" binding:"required"`
+	Token string `header:"X-Token" json:"-"`
+	Name  string `json:"name" form:"name"`
+}
+
+func TestHandleUri(t *testing.T) {
+	var got tHandleUriReq
+	h := Handle(func(ctx context.Context, req *tHandleUriReq) (any, error) {
+		got = *req
+		return nil, nil
+	})
+	r := gin.New()
+	r.DELETE("/order/:id", h)
+	r.PUT("/order/:id", h)
+
+	req := httptest.NewRequest(http.MethodDelete, "/order/42?name=a", nil)
+	req.Header.Set("X-Token", "t1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || got.Id != 42 || got.Token != "t1" || got.Name != "a" {
+		t.Errorf("DELETE /order/42 = %s, %+v", w.Body.String(), got)
+	}
+
+	got = tHandleUriReq{}
+	req = httptest.NewRequest(http.MethodPut, "/order/7", strings.NewReader(`{"name":"b"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if got.Id != 7 || got.Name != "b" {
+		t.Errorf("PUT /order/7 = %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/order/abc", nil))
+	if !strings.Contains(w.Body.String(), `"code":400`) {
+		t.Errorf("DELETE /order/abc = %s", w.Body.String())
+	}
+}
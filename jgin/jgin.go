@@ -36,12 +36,15 @@ type tWithNow interface {
 type tWithIP interface {
 	SetIP(string)
 }
+type tWithUser interface {
+	SetUser(u *User)
+}
 type tWithCtx interface {
 	SetCtx(ctx context.Context)
 	SetCtxValue(k, v interface{})
 }
 
-// GinMustBind 示例：解析请求参数
+// GinMustBind 示例：解析请求参数，校验失败时返回带字段信息的 jerrno.BadRequest，见 ValidationError
 func GinMustBind(c *gin.Context, obj interface{}) error {
 	reqMethod, reqContentType := c.Request.Method, c.ContentType()
 	var b binding.Binding = nil
@@ -57,7 +60,7 @@ func GinMustBind(c *gin.Context, obj interface{}) error {
 	}
 	err := c.MustBindWith(obj, b)
 	if err != nil {
-		return err
+		return ValidationError(err, obj, RequestLocales(c)...)
 	}
 	if m, ok := obj.(tWithNow); ok {
 		m.SetNow(time.Now())
@@ -68,6 +71,11 @@ func GinMustBind(c *gin.Context, obj interface{}) error {
 	if m, ok := obj.(tWithIP); ok {
 		m.SetIP(c.ClientIP())
 	}
+	if m, ok := obj.(tWithUser); ok {
+		if u := UserOf(c); u != nil {
+			m.SetUser(u)
+		}
+	}
 	if m, ok := obj.(tWithBinder); ok {
 		err = m.BindGinContext(c)
 		if err != nil {
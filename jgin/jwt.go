@@ -0,0 +1,396 @@
+package jgin
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JWT 的签发与校验，支持 HS256、RS256，只依赖标准库
+
+const (
+	JWTHS256 = "HS256"
+	JWTRS256 = "RS256"
+)
+
+var (
+	ErrTokenMissing    = errors.New("jgin: 缺少 token")
+	ErrTokenMalformed  = errors.New("jgin: token 格式有误")
+	ErrTokenSignature  = errors.New("jgin: token 签名无效")
+	ErrTokenExpired    = errors.New("jgin: token 已过期")
+	ErrTokenNotValid   = errors.New("jgin: token 尚未生效")
+	ErrTokenClaims     = errors.New("jgin: token 的签发者或接收方不匹配")
+	ErrTokenMissingExp = errors.New("jgin: token 缺少 exp")
+	ErrTokenBadTime    = errors.New("jgin: token 的 exp 或 nbf 不是有效的时间")
+	ErrTokenSubject    = errors.New("jgin: token 中没有用户")
+)
+
+// JWTConfig JWT 配置，密钥可以是 PEM 内容，也可以是 PEM 文件的路径
+type JWTConfig struct {
+	Algorithm  string `mapstructure:"algorithm,omitempty" json:"algorithm,omitempty" yaml:"algorithm,omitempty" toml:"algorithm,omitempty"`     // HS256（默认）、RS256
+	Secret     string `mapstructure:"secret,omitempty" json:"secret,omitempty" yaml:"secret,omitempty" toml:"secret,omitempty"`                 // HS256 的密钥
+	PublicKey  string `mapstructure:"publicKey,omitempty" json:"publicKey,omitempty" yaml:"publicKey,omitempty" toml:"publicKey,omitempty"`     // RS256 校验用的公钥或证书
+	PrivateKey string `mapstructure:"privateKey,omitempty" json:"privateKey,omitempty" yaml:"privateKey,omitempty" toml:"privateKey,omitempty"` // RS256 签发用的私钥，只校验时可以不填
+	Issuer     string `mapstructure:"issuer,omitempty" json:"issuer,omitempty" yaml:"issuer,omitempty" toml:"issuer,omitempty"`                 // 非空时校验 iss
+	Audience   string `mapstructure:"audience,omitempty" json:"audience,omitempty" yaml:"audience,omitempty" toml:"audience,omitempty"`         // 非空时校验 aud
+	LeewaySec  int    `mapstructure:"leewaySec,omitempty" json:"leewaySec,omitempty" yaml:"leewaySec,omitempty" toml:"leewaySec,omitempty"`     // 校验 exp、nbf 时允许的时钟偏差（秒）
+	ExpireSec  int    `mapstructure:"expireSec,omitempty" json:"expireSec,omitempty" yaml:"expireSec,omitempty" toml:"expireSec,omitempty"`     // 签发时的有效期（秒），默认 7200
+	RequireExp *bool  `mapstructure:"requireExp,omitempty" json:"requireExp,omitempty" yaml:"requireExp,omitempty" toml:"requireExp,omitempty"` // 是否拒绝没有 exp 的 token，默认 true
+
+	Header     string `mapstructure:"header,omitempty" json:"header,omitempty" yaml:"header,omitempty" toml:"header,omitempty"`                 // 读取 token 的请求头，默认 Authorization，可以带 Bearer 前缀
+	Query      string `mapstructure:"query,omitempty" json:"query,omitempty" yaml:"query,omitempty" toml:"query,omitempty"`                     // 读取 token 的查询参数，为空时不读取
+	Cookie     string `mapstructure:"cookie,omitempty" json:"cookie,omitempty" yaml:"cookie,omitempty" toml:"cookie,omitempty"`                 // 读取 token 的 cookie，为空时不读取
+	Optional   bool   `mapstructure:"optional,omitempty" json:"optional,omitempty" yaml:"optional,omitempty" toml:"optional,omitempty"`         // 没有 token 时不拦截，作为匿名用户继续处理
+	RolesClaim string `mapstructure:"rolesClaim,omitempty" json:"rolesClaim,omitempty" yaml:"rolesClaim,omitempty" toml:"rolesClaim,omitempty"` // 角色所在的字段，默认 roles
+	PermsClaim string `mapstructure:"permsClaim,omitempty" json:"permsClaim,omitempty" yaml:"permsClaim,omitempty" toml:"permsClaim,omitempty"` // 权限所在的字段，默认 perms
+	NameClaim  string `mapstructure:"nameClaim,omitempty" json:"nameClaim,omitempty" yaml:"nameClaim,omitempty" toml:"nameClaim,omitempty"`     // 用户名所在的字段，默认 name
+}
+
+func (j *JWTConfig) FixDefault() {
+	if j.Algorithm == "" {
+		j.Algorithm = JWTHS256
+	}
+	if j.ExpireSec <= 0 {
+		j.ExpireSec = 7200
+	}
+	if j.RequireExp == nil {
+		requireExp := true
+		j.RequireExp = &requireExp
+	}
+	if j.Header == "" {
+		j.Header = "Authorization"
+	}
+	if j.RolesClaim == "" {
+		j.RolesClaim = "roles"
+	}
+	if j.PermsClaim == "" {
+		j.PermsClaim = "perms"
+	}
+	if j.NameClaim == "" {
+		j.NameClaim = "name"
+	}
+}
+
+// JWTClaims token 中的数据
+type JWTClaims map[string]any
+
+// Subject sub
+func (c JWTClaims) Subject() string {
+	return c.String("sub")
+}
+
+// String 字符串字段，数字也转为字符串
+func (c JWTClaims) String(key string) string {
+	switch v := c[key].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// Strings 字符串数组字段，也支持空格分隔的字符串，如 scope
+func (c JWTClaims) Strings(key string) []string {
+	switch v := c[key].(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []any:
+		ss := make([]string, 0, len(v))
+		for _, s := range v {
+			if s1, ok := s.(string); ok {
+				ss = append(ss, s1)
+			}
+		}
+		return ss
+	}
+	return nil
+}
+
+// Time 时间字段，如 exp、nbf、iat
+func (c JWTClaims) Time(key string) (time.Time, bool) {
+	switch v := c[key].(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return time.Unix(n, 0), true
+		}
+		if f, err := v.Float64(); err == nil {
+			return time.Unix(int64(f), 0), true
+		}
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case int64:
+		return time.Unix(v, 0), true
+	case int:
+		return time.Unix(int64(v), 0), true
+	}
+	return time.Time{}, false
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// JWT 按配置签发、校验 token
+type JWT struct {
+	conf    JWTConfig
+	secret  []byte
+	pubKey  *rsa.PublicKey
+	privKey *rsa.PrivateKey
+	mapper  func(claims JWTClaims) (*User, error)
+}
+
+// NewJWT 按配置创建，解析密钥
+func NewJWT(conf JWTConfig) (*JWT, error) {
+	conf.FixDefault()
+	j := &JWT{conf: conf}
+	switch conf.Algorithm {
+	case JWTHS256:
+		if conf.Secret == "" {
+			return nil, errors.New("jgin: HS256 需要配置 secret")
+		}
+		j.secret = []byte(conf.Secret)
+	case JWTRS256:
+		if conf.PrivateKey != "" {
+			key, err := parseRSAPrivateKey(conf.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			j.privKey, j.pubKey = key, &key.PublicKey
+		}
+		if conf.PublicKey != "" {
+			key, err := parseRSAPublicKey(conf.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+			j.pubKey = key
+		}
+		if j.pubKey == nil {
+			return nil, errors.New("jgin: RS256 需要配置 publicKey 或 privateKey")
+		}
+	default:
+		return nil, fmt.Errorf("jgin: 不支持的 JWT 算法 %q", conf.Algorithm)
+	}
+	return j, nil
+}
+
+// Config 补全默认值后的配置
+func (j *JWT) Config() JWTConfig {
+	return j.conf
+}
+
+// Sign 签发 token，没有 exp、iat、iss、aud 时按配置补上
+func (j *JWT) Sign(claims JWTClaims) (string, error) {
+	now := time.Now()
+	c := make(JWTClaims, len(claims)+3)
+	for k, v := range claims {
+		c[k] = v
+	}
+	if _, ok := c["iat"]; !ok {
+		c["iat"] = now.Unix()
+	}
+	if _, ok := c["exp"]; !ok {
+		c["exp"] = now.Add(time.Duration(j.conf.ExpireSec) * time.Second).Unix()
+	}
+	if _, ok := c["iss"]; !ok && j.conf.Issuer != "" {
+		c["iss"] = j.conf.Issuer
+	}
+	if _, ok := c["aud"]; !ok && j.conf.Audience != "" {
+		c["aud"] = j.conf.Audience
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": j.conf.Algorithm, "typ": "JWT"})
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	signing := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	sig, err := j.sign([]byte(signing))
+	if err != nil {
+		return "", err
+	}
+	return signing + "." + b64.EncodeToString(sig), nil
+}
+
+func (j *JWT) sign(data []byte) ([]byte, error) {
+	if j.conf.Algorithm == JWTHS256 {
+		h := hmac.New(sha256.New, j.secret)
+		h.Write(data)
+		return h.Sum(nil), nil
+	}
+	if j.privKey == nil {
+		return nil, errors.New("jgin: RS256 签发需要配置 privateKey")
+	}
+	sum := sha256.Sum256(data)
+	return rsa.SignPKCS1v15(rand.Reader, j.privKey, crypto.SHA256, sum[:])
+}
+
+// Verify 校验 token 的签名、算法、exp、nbf、iss、aud
+func (j *JWT) Verify(token string) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrTokenMalformed
+	}
+	// 只接受配置的算法，避免 alg=none、HS256/RS256 混用等攻击
+	if header.Alg != j.conf.Algorithm {
+		return nil, ErrTokenSignature
+	}
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+	if !j.verifySignature([]byte(parts[0]+"."+parts[1]), sig) {
+		return nil, ErrTokenSignature
+	}
+	var claims JWTClaims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrTokenMalformed
+	}
+	return claims, j.validate(claims, time.Now())
+}
+
+func (j *JWT) verifySignature(data, sig []byte) bool {
+	if j.conf.Algorithm == JWTHS256 {
+		h := hmac.New(sha256.New, j.secret)
+		h.Write(data)
+		return hmac.Equal(h.Sum(nil), sig)
+	}
+	sum := sha256.Sum256(data)
+	return rsa.VerifyPKCS1v15(j.pubKey, crypto.SHA256, sum[:], sig) == nil
+}
+
+func (j *JWT) validate(claims JWTClaims, now time.Time) error {
+	leeway := time.Duration(j.conf.LeewaySec) * time.Second
+	// exp、nbf 存在但不是数字时视为无效，避免被当作没有设置而绕过校验
+	if _, ok := claims["exp"]; ok {
+		exp, ok := claims.Time("exp")
+		if !ok {
+			return ErrTokenBadTime
+		}
+		if !now.Before(exp.Add(leeway)) {
+			return ErrTokenExpired
+		}
+	} else if *j.conf.RequireExp {
+		return ErrTokenMissingExp
+	}
+	if _, ok := claims["nbf"]; ok {
+		nbf, ok := claims.Time("nbf")
+		if !ok {
+			return ErrTokenBadTime
+		}
+		if now.Add(leeway).Before(nbf) {
+			return ErrTokenNotValid
+		}
+	}
+	if j.conf.Issuer != "" && claims.String("iss") != j.conf.Issuer {
+		return ErrTokenClaims
+	}
+	if j.conf.Audience != "" {
+		aud := claims.Strings("aud")
+		found := false
+		for _, a := range aud {
+			if a == j.conf.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrTokenClaims
+		}
+	}
+	return nil
+}
+
+var b64 = base64.RawURLEncoding
+
+func decodeSegment(s string, v any) error {
+	b, err := b64.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// readPEM 内容以 -----BEGIN 开头时直接使用，否则作为文件路径读取
+func readPEM(s string) (*pem.Block, error) {
+	b := []byte(s)
+	if !strings.HasPrefix(strings.TrimSpace(s), "-----BEGIN") {
+		var err error
+		if b, err = os.ReadFile(s); err != nil {
+			return nil, err
+		}
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("jgin: 无效的 PEM 密钥")
+	}
+	return block, nil
+}
+
+func parseRSAPublicKey(s string) (*rsa.PublicKey, error) {
+	block, err := readPEM(s)
+	if err != nil {
+		return nil, err
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	default:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := key.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, errors.New("jgin: 不是 RSA 公钥")
+}
+
+func parseRSAPrivateKey(s string) (*rsa.PrivateKey, error) {
+	block, err := readPEM(s)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := key.(*rsa.PrivateKey); ok {
+		return key, nil
+	}
+	return nil, errors.New("jgin: 不是 RSA 私钥")
+}
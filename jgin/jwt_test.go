@@ -0,0 +1,171 @@
+package jgin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestJWT(t *testing.T) {
+	j, err := NewJWT(JWTConfig{Secret: "s3cret", Issuer: "jkit", Audience: "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := j.Sign(JWTClaims{"sub": "42", "roles": []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := j.Verify(token)
+	if err != nil || claims.Subject() != "42" || claims.Strings("roles")[0] != "admin" || claims.String("iss") != "jkit" {
+		t.Errorf("Verify() = %v, %v", claims, err)
+	}
+
+	if _, err = j.Verify(token[:len(token)-2] + "xx"); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("Verify(篡改) = %v", err)
+	}
+	expired, _ := j.Sign(JWTClaims{"sub": "42", "exp": time.Now().Add(-time.Minute).Unix()})
+	if _, err = j.Verify(expired); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Verify(过期) = %v", err)
+	}
+	// exp、nbf 无法解析或缺少 exp
+	plain, _ := NewJWT(JWTConfig{Secret: "s3cret"})
+	if _, err = plain.Verify(signHS256(t, "s3cret", `{"sub":"42","exp":9999999999}`)); err != nil {
+		t.Errorf("Verify(exp) = %v", err)
+	}
+	for payload, want := range map[string]error{
+		`{"sub":"42","exp":"9999999999"}`:         ErrTokenBadTime,
+		`{"sub":"42","exp":null}`:                 ErrTokenBadTime,
+		`{"sub":"42","exp":9999999999,"nbf":[1]}`: ErrTokenBadTime,
+		`{"sub":"42"}`:                            ErrTokenMissingExp,
+	} {
+		if _, err = plain.Verify(signHS256(t, "s3cret", payload)); !errors.Is(err, want) {
+			t.Errorf("Verify(%s) = %v, want %v", payload, err, want)
+		}
+	}
+	requireExp := false
+	j3, _ := NewJWT(JWTConfig{Secret: "s3cret", RequireExp: &requireExp})
+	if _, err = j3.Verify(signHS256(t, "s3cret", `{"sub":"42"}`)); err != nil {
+		t.Errorf("Verify(RequireExp=false) = %v", err)
+	}
+	j2, _ := NewJWT(JWTConfig{Secret: "s3cret", Audience: "other"})
+	if _, err = j2.Verify(token); !errors.Is(err, ErrTokenClaims) {
+		t.Errorf("Verify(aud) = %v", err)
+	}
+	none := b64.EncodeToString([]byte(`{"alg":"none"}`)) + "." + strings.Split(token, ".")[1] + "."
+	if _, err = j.Verify(none); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("Verify(alg=none) = %v", err)
+	}
+}
+
+func TestJWTRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pubDer, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer})
+
+	signer, err := NewJWT(JWTConfig{Algorithm: JWTRS256, PrivateKey: string(priv)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewJWT(JWTConfig{Algorithm: JWTRS256, PublicKey: string(pub)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := signer.Sign(JWTClaims{"sub": "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, err := verifier.Verify(token); err != nil || c.Subject() != "7" {
+		t.Errorf("Verify() = %v, %v", c, err)
+	}
+	if _, err = verifier.Sign(JWTClaims{}); err == nil {
+		t.Error("只有公钥时不能签发")
+	}
+	// 用公钥当作 HS256 的密钥伪造
+	forger, _ := NewJWT(JWTConfig{Secret: string(pub)})
+	forged, _ := forger.Sign(JWTClaims{"sub": "7"})
+	if _, err = verifier.Verify(forged); !errors.Is(err, ErrTokenSignature) {
+		t.Errorf("Verify(HS256 伪造) = %v", err)
+	}
+}
+
+type tAuthReq struct {
+	ReqWithUser
+}
+
+func TestAuth(t *testing.T) {
+	j, _ := NewJWT(JWTConfig{Secret: "s3cret"})
+	r := gin.New()
+	var lastErr error
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		lastErr = ResponseError(c)
+	})
+	api := r.Group("/api", j.Auth())
+	api.GET("/me", Handle(func(ctx context.Context, req *tAuthReq) (*User, error) {
+		return req.GetUser(), nil
+	}))
+	api.GET("/order", RequirePerms("order:read"), Ok)
+	api.GET("/admin", RequireRoles("admin"), Ok)
+	jNil, _ := NewJWT(JWTConfig{Secret: "s3cret"})
+	jNil.SetClaimsMapper(func(claims JWTClaims) (*User, error) { return nil, nil })
+	r.GET("/nil/me", jNil.Auth(), RequireRoles(), Ok)
+
+	token, _ := j.Sign(JWTClaims{"sub": "42", "name": "tom", "perms": []string{"order:*"}})
+	do := func(path, token string) string {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	cases := []struct {
+		path, token, want string
+	}{
+		{"/api/me", token, `{"code":0,"data":{"id":"42","name":"tom","perms":["order:*"]},"msg":"操作成功"}`},
+		{"/api/me", "", `{"code":401,"msg":"请先登录"}`},
+		{"/api/me", "bad.token.x", `{"code":401,"msg":"请先登录"}`},
+		{"/api/order", token, `{"code":0,"msg":"操作成功"}`},
+		{"/api/admin", token, `{"code":403,"msg":"权限不足"}`},
+		{"/nil/me", token, `{"code":401,"msg":"请先登录"}`},
+	}
+	for _, v := range cases {
+		if got := do(v.path, v.token); got != v.want {
+			t.Errorf("%s = %s, want %s", v.path, got, v.want)
+		}
+	}
+
+	// 没有用户
+	noSub, _ := j.Sign(JWTClaims{"name": "tom"})
+	for path, token := range map[string]string{"/api/me": noSub, "/nil/me": token} {
+		if do(path, token); !errors.Is(lastErr, ErrTokenSubject) {
+			t.Errorf("%s 没有用户 = %v", path, lastErr)
+		}
+	}
+}
+
+// signHS256 按原样签名 payload，用于构造 Sign 不会生成的 token
+func signHS256(t *testing.T, secret, payload string) string {
+	t.Helper()
+	signing := b64.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + b64.EncodeToString([]byte(payload))
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(signing))
+	return signing + "." + b64.EncodeToString(h.Sum(nil))
+}
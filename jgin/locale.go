@@ -0,0 +1,79 @@
+package jgin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 识别请求的语言，用于本地化错误信息
+
+// LocaleConfig 语言的来源，优先级：查询参数 > 自定义头 > Accept-Language
+type LocaleConfig struct {
+	Query  string `mapstructure:"query,omitempty" json:"query,omitempty" yaml:"query,omitempty" toml:"query,omitempty"`     // 查询参数，如 lang
+	Header string `mapstructure:"header,omitempty" json:"header,omitempty" yaml:"header,omitempty" toml:"header,omitempty"` // 自定义头，如 X-Lang
+}
+
+var localeConfig = LocaleConfig{Query: "lang"}
+
+// SetLocaleConfig 设置语言的来源
+func SetLocaleConfig(conf LocaleConfig) {
+	localeConfig = conf
+}
+
+// RequestLocales 请求可接受的语言，按优先级排序
+func RequestLocales(c *gin.Context) []string {
+	var locales []string
+	if localeConfig.Query != "" {
+		if s := c.Query(localeConfig.Query); s != "" {
+			locales = append(locales, s)
+		}
+	}
+	if localeConfig.Header != "" {
+		if s := c.GetHeader(localeConfig.Header); s != "" {
+			locales = append(locales, s)
+		}
+	}
+	return append(locales, ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+}
+
+// ParseAcceptLanguage 解析 Accept-Language，按权重从高到低返回，忽略 * 与 q=0
+//
+//	如 "en-US,en;q=0.9,zh;q=0.8" => [en-US en zh]
+func ParseAcceptLanguage(s string) []string {
+	if s == "" {
+		return nil
+	}
+	type tLang struct {
+		tag string
+		q   float64
+	}
+	var langs []tLang
+	for _, part := range strings.Split(s, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, tLang{tag, q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+	tags := make([]string, len(langs))
+	for i, v := range langs {
+		tags[i] = v.tag
+	}
+	return tags
+}
@@ -0,0 +1,22 @@
+package jgin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, v := range []struct {
+		s    string
+		want []string
+	}{
+		{"", nil},
+		{"en", []string{"en"}},
+		{"zh;q=0.8, en-US,en;q=0.9", []string{"en-US", "en", "zh"}},
+		{"fr;q=0, *;q=0.5, de", []string{"de"}},
+	} {
+		if got := ParseAcceptLanguage(v.s); !reflect.DeepEqual(got, v.want) && !(len(got) == 0 && len(v.want) == 0) {
+			t.Errorf("ParseAcceptLanguage(%q) = %v, want %v", v.s, got, v.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package jgin
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 按路由、业务码、http 状态码统计响应次数，通过 expvar 与 Prometheus 文本格式输出。
+//
+//	r.Use(jgin.CodeMetricsMiddleware())
+//	r.GET("/metrics", jgin.CodeMetricsHandler())
+//
+// 输出示例：
+//
+//	jgin_responses_total{route="/order/:id",code="551",http_code="200"} 3
+
+// MetricsName expvar 中的名称，也是 Prometheus 指标名的前缀
+const MetricsName = "jgin_responses"
+
+// CodeCount 一组统计
+type CodeCount struct {
+	Route    string `json:"route"` // 路由，即 c.FullPath()，未匹配到路由时为空
+	Code     int    `json:"code"`
+	HttpCode int    `json:"httpCode"`
+	Count    uint64 `json:"count"`
+}
+
+type codeKey struct {
+	route          string
+	code, httpCode int
+}
+
+// CodeMetrics 响应次数统计，实现了 expvar.Var
+type CodeMetrics struct {
+	mu     sync.RWMutex
+	counts map[codeKey]*atomic.Uint64
+}
+
+// DefaultCodeMetrics 默认的统计，已发布到 expvar
+var DefaultCodeMetrics = NewCodeMetrics()
+
+func init() {
+	expvar.Publish(MetricsName, DefaultCodeMetrics)
+}
+
+func NewCodeMetrics() *CodeMetrics {
+	return &CodeMetrics{counts: make(map[codeKey]*atomic.Uint64)}
+}
+
+// Inc 计数加一
+func (m *CodeMetrics) Inc(route string, code, httpCode int) {
+	k := codeKey{route, code, httpCode}
+	m.mu.RLock()
+	n, ok := m.counts[k]
+	m.mu.RUnlock()
+	if !ok {
+		m.mu.Lock()
+		if n, ok = m.counts[k]; !ok {
+			n = new(atomic.Uint64)
+			m.counts[k] = n
+		}
+		m.mu.Unlock()
+	}
+	n.Add(1)
+}
+
+// Snapshot 当前的统计，按路由、业务码、http 状态码排序
+func (m *CodeMetrics) Snapshot() []CodeCount {
+	m.mu.RLock()
+	items := make([]CodeCount, 0, len(m.counts))
+	for k, n := range m.counts {
+		items = append(items, CodeCount{Route: k.route, Code: k.code, HttpCode: k.httpCode, Count: n.Load()})
+	}
+	m.mu.RUnlock()
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.HttpCode < b.HttpCode
+	})
+	return items
+}
+
+// String expvar.Var，以 json 数组输出
+func (m *CodeMetrics) String() string {
+	b, _ := json.Marshal(m.Snapshot())
+	return string(b)
+}
+
+// Middleware 中间件，统计经 Result、ResultErr 返回的响应
+func (m *CodeMetrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if code, ok := ResponseCode(c); ok {
+			m.Inc(c.FullPath(), code, c.Writer.Status())
+		}
+	}
+}
+
+// Handler 以 Prometheus 文本格式输出
+func (m *CodeMetrics) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b strings.Builder
+		b.WriteString("# HELP " + MetricsName + "_total 按路由、业务码、http 状态码统计的响应次数\n")
+		b.WriteString("# TYPE " + MetricsName + "_total counter\n")
+		for _, v := range m.Snapshot() {
+			fmt.Fprintf(&b, "%s_total{route=\"%s\",code=\"%d\",http_code=\"%d\"} %d\n",
+				MetricsName, promEscaper.Replace(v.Route), v.Code, v.HttpCode, v.Count)
+		}
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+	}
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// CodeMetricsMiddleware 使用 DefaultCodeMetrics 统计
+func CodeMetricsMiddleware() gin.HandlerFunc {
+	return DefaultCodeMetrics.Middleware()
+}
+
+// CodeMetricsHandler 输出 DefaultCodeMetrics
+func CodeMetricsHandler() gin.HandlerFunc {
+	return DefaultCodeMetrics.Handler()
+}
@@ -0,0 +1,40 @@
+package jgin
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+func TestCodeMetrics(t *testing.T) {
+	m := NewCodeMetrics()
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/order/:id", func(c *gin.Context) {
+		ResultErr(nil, jerrno.QueryFailed, c)
+	})
+	r.GET("/ok", Ok)
+	r.GET("/metrics", m.Handler())
+
+	for _, path := range []string{"/order/1", "/order/2", "/ok", "/metrics"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	items := m.Snapshot()
+	if len(items) != 2 || items[0].Route != "/ok" || items[1].Code != 551 || items[1].Count != 2 {
+		t.Errorf("Snapshot() = %+v", items)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), `jgin_responses_total{route="/order/:id",code="551",http_code="200"} 2`) {
+		t.Errorf("Handler() = %s", w.Body.String())
+	}
+	if expvar.Get(MetricsName) == nil {
+		t.Error("DefaultCodeMetrics 没有发布到 expvar")
+	}
+}
@@ -0,0 +1,510 @@
+package jgin
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jgormtypes"
+	"github.com/xtulnx/jkit-go/jtypes"
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI 3 文档，由类型化注册的路由（见 GET、POST 等）及请求、响应的结构生成。
+//
+// 结构字段使用的标签：
+//
+//	json    请求体、响应的字段名
+//	form    GET、DELETE 请求的查询参数
+//	uri     路径参数
+//	header  请求头参数
+//	binding 含 required 时为必填
+//	example 示例值
+//	doc     字段说明
+//
+// 自定义类型的格式用 RegOpenAPISchema 登记，如 jtypes.JPrice、jgormtypes.JDate。
+
+// OpenAPIVersion 生成的文档版本
+const OpenAPIVersion = "3.0.3"
+
+// OpenAPIInfo 文档的基本信息
+type OpenAPIInfo struct {
+	Title       string `mapstructure:"title,omitempty" json:"title" yaml:"title" toml:"title,omitempty"`
+	Description string `mapstructure:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Version     string `mapstructure:"version,omitempty" json:"version" yaml:"version" toml:"version,omitempty"`
+}
+
+// OpenAPIDoc OpenAPI 文档
+type OpenAPIDoc struct {
+	OpenAPI    string                              `json:"openapi"`
+	Info       OpenAPIInfo                         `json:"info"`
+	Paths      map[string]map[string]*APIOperation `json:"paths"`
+	Components APIComponents                       `json:"components"`
+}
+
+type APIComponents struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// APIOperation 一个接口
+type APIOperation struct {
+	OperationID string                  `json:"operationId,omitempty"`
+	Summary     string                  `json:"summary,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
+	Parameters  []*APIParameter         `json:"parameters,omitempty"`
+	RequestBody *APIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*APIResponse `json:"responses"`
+}
+
+type APIParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path、query、header
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type APIRequestBody struct {
+	Required bool                    `json:"required,omitempty"`
+	Content  map[string]APIMediaType `json:"content"`
+}
+
+type APIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]APIMediaType `json:"content,omitempty"`
+}
+
+type APIMediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema 数据结构
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Example              any                `json:"example,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+var apiSchemas = map[reflect.Type]Schema{}
+
+// RegOpenAPISchema 登记类型在文档中的格式，v 为该类型的值
+//
+//	jgin.RegOpenAPISchema(jtypes.JPrice(0), jgin.Schema{Type: "number", Format: "decimal"})
+func RegOpenAPISchema(v any, s Schema) {
+	apiSchemas[reflect.TypeOf(v)] = s
+}
+
+func init() {
+	RegOpenAPISchema(time.Time{}, Schema{Type: "string", Format: "date-time"})
+	RegOpenAPISchema(time.Duration(0), Schema{Type: "integer", Format: "int64", Description: "纳秒"})
+	RegOpenAPISchema(jgormtypes.JDate{}, Schema{Type: "string", Format: "date", Example: "2006-01-02"})
+	// JDateTime、JTime 不带时区，不符合 date-time、time 格式的要求，用 pattern 描述
+	RegOpenAPISchema(jgormtypes.JDateTime{}, Schema{Type: "string", Pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`, Example: "2006-01-02T15:04:05"})
+	RegOpenAPISchema(jgormtypes.JTime(0), Schema{Type: "string", Pattern: `^\d{2}:\d{2}:\d{2}(\.\d+)?$`, Example: "15:04:05"})
+	RegOpenAPISchema(jtypes.JPrice(0), Schema{Type: "number", Format: "decimal", Example: 12.34})
+	RegOpenAPISchema(JIDs(""), Schema{Type: "string", Pattern: `^\d+(,\d+)*$`, Example: "1,2,3", Description: "逗号分隔的 id"})
+	RegOpenAPISchema(JDates(""), Schema{Type: "string", Format: "date", Example: "2006-01-02"})
+}
+
+var (
+	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaBuilder 生成 Schema，结构体放入 components 并引用
+type schemaBuilder struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+func (b *schemaBuilder) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s, ok := apiSchemas[t]; ok {
+		return &s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64", Minimum: new(float64)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: new(float64)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	}
+	if t.Implements(typeTextMarshaler) || reflect.PointerTo(t).Implements(typeTextMarshaler) {
+		return &Schema{Type: "string"}
+	}
+	if t.Implements(typeJSONMarshaler) || reflect.PointerTo(t).Implements(typeJSONMarshaler) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name, ok := b.names[t]
+		if !ok {
+			name = schemaName(t)
+			b.names[t] = name
+			b.schemas[name] = &Schema{}
+			*b.schemas[name] = *b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// structSchema 按 json 标签生成，匿名嵌入的结构体展开
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	eachField(t, "json", func(f reflect.StructField, name string) {
+		fs := b.schemaOf(f.Type)
+		if fs.Ref == "" {
+			fs.Description = fieldDoc(f, fs.Description)
+			if v, ok := f.Tag.Lookup("example"); ok {
+				fs.Example = exampleValue(fs.Type, v)
+			}
+		}
+		s.Properties[name] = fs
+		if isRequired(f) {
+			s.Required = append(s.Required, name)
+		}
+	})
+	return s
+}
+
+// eachField 遍历导出的字段，标签为 "-" 的跳过；没有标签的使用字段名，匿名嵌入的结构体展开
+func eachField(t reflect.Type, tag string, fn func(f reflect.StructField, name string)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if _, ok := apiSchemas[ft]; !ok {
+				eachField(ft, tag, fn)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fn(f, name)
+	}
+}
+
+func isRequired(f reflect.StructField) bool {
+	for _, v := range strings.Split(f.Tag.Get("binding"), ",") {
+		if v == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldDoc(f reflect.StructField, def string) string {
+	if s := f.Tag.Get("doc"); s != "" {
+		return s
+	}
+	return def
+}
+
+// exampleValue 按类型转换示例值
+func exampleValue(typ, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if n, err := strconv.ParseBool(v); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+var (
+	schemaPkgPath     = regexp.MustCompile(`[\w.\-]*/`)
+	schemaInvalidChar = regexp.MustCompile(`[^\w.\-]+`)
+)
+
+// schemaName 包名.类型名，泛型参数去掉包路径，如 jgin.PageResult_model.User_
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndexByte(pkg, '/'); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	name := schemaPkgPath.ReplaceAllString(t.Name(), "")
+	name = schemaInvalidChar.ReplaceAllString(name, "_")
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// apiRoute 登记的路由
+type apiRoute struct {
+	method, path string
+	req, resp    reflect.Type
+	doc          RouteDoc
+}
+
+// OpenAPI 收集路由并生成文档
+type OpenAPI struct {
+	mu     sync.RWMutex
+	info   OpenAPIInfo
+	routes []apiRoute
+}
+
+// DefaultOpenAPI GET、POST 等注册的路由记录在这里
+var DefaultOpenAPI = NewOpenAPI(OpenAPIInfo{Title: "API", Version: "1.0.0"})
+
+func NewOpenAPI(info OpenAPIInfo) *OpenAPI {
+	return &OpenAPI{info: info}
+}
+
+// SetInfo 设置文档的基本信息
+func (o *OpenAPI) SetInfo(info OpenAPIInfo) {
+	o.mu.Lock()
+	o.info = info
+	o.mu.Unlock()
+}
+
+// Add 登记路由，path 为完整路径，如 /api/order/:id
+func (o *OpenAPI) Add(method, path string, req, resp reflect.Type, doc RouteDoc) {
+	o.mu.Lock()
+	o.routes = append(o.routes, apiRoute{method: method, path: path, req: req, resp: resp, doc: doc})
+	o.mu.Unlock()
+}
+
+// Doc 生成文档
+func (o *OpenAPI) Doc() *OpenAPIDoc {
+	o.mu.RLock()
+	info, routes := o.info, append([]apiRoute(nil), o.routes...)
+	o.mu.RUnlock()
+
+	b := newSchemaBuilder()
+	doc := &OpenAPIDoc{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]map[string]*APIOperation),
+	}
+	for _, r := range routes {
+		path := openAPIPath(r.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*APIOperation)
+		}
+		doc.Paths[path][strings.ToLower(r.method)] = b.operation(r)
+	}
+	doc.Components.Schemas = b.schemas
+	return doc
+}
+
+func (b *schemaBuilder) operation(r apiRoute) *APIOperation {
+	op := &APIOperation{
+		OperationID: r.doc.OperationID,
+		Summary:     r.doc.Summary,
+		Description: r.doc.Description,
+		Tags:        r.doc.Tags,
+		Deprecated:  r.doc.Deprecated,
+	}
+	req := r.req
+	for req != nil && req.Kind() == reflect.Pointer {
+		req = req.Elem()
+	}
+	if req != nil && req.Kind() == reflect.Struct {
+		op.Parameters = append(op.Parameters, b.parameters(req, "uri", "path")...)
+		op.Parameters = append(op.Parameters, b.parameters(req, "header", "header")...)
+		switch r.method {
+		case http.MethodGet, http.MethodDelete, http.MethodHead:
+			op.Parameters = append(op.Parameters, b.parameters(req, "form", "query")...)
+		default:
+			op.RequestBody = &APIRequestBody{
+				Required: true,
+				Content:  map[string]APIMediaType{"application/json": {Schema: b.schemaOf(req)}},
+			}
+		}
+	}
+
+	data := &Schema{}
+	if r.resp != nil {
+		data = b.schemaOf(r.resp)
+	}
+	op.Responses = map[string]*APIResponse{
+		"200": {
+			Description: "code 为 0 表示成功，否则为错误码",
+			Content: map[string]APIMediaType{"application/json": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"code":    {Type: "integer", Example: 0},
+					"msg":     {Type: "string"},
+					"data":    data,
+					"details": {Type: "object", Description: "错误的详细信息"},
+					"fields":  {Type: "array", Description: "字段错误", Items: b.schemaOf(reflect.TypeOf(jerrno.FieldError{}))},
+				},
+				Required: []string{"code", "msg"},
+			}}},
+		},
+	}
+	return op
+}
+
+// parameters 有 tag 标签的字段作为参数；query 参数没有标签时使用字段名
+func (b *schemaBuilder) parameters(t reflect.Type, tag, in string) []*APIParameter {
+	var params []*APIParameter
+	eachField(t, tag, func(f reflect.StructField, name string) {
+		if _, ok := f.Tag.Lookup(tag); !ok && in != "query" {
+			return
+		}
+		if in == "query" && (f.Tag.Get("uri") != "" || f.Tag.Get("header") != "") {
+			return
+		}
+		s := b.schemaOf(f.Type)
+		if v, ok := f.Tag.Lookup("example"); ok && s.Ref == "" {
+			s.Example = exampleValue(s.Type, v)
+		}
+		params = append(params, &APIParameter{
+			Name:        name,
+			In:          in,
+			Description: fieldDoc(f, ""),
+			Required:    in == "path" || isRequired(f),
+			Schema:      s,
+		})
+	})
+	return params
+}
+
+var ginPathParam = regexp.MustCompile(`[:*](\w+)`)
+
+// openAPIPath gin 的路径转为 OpenAPI 格式，如 /order/:id => /order/{id}
+func openAPIPath(path string) string {
+	return ginPathParam.ReplaceAllString(path, "{$1}")
+}
+
+// JSON 以 json 格式输出文档
+func (o *OpenAPI) JSON() ([]byte, error) {
+	return json.MarshalIndent(o.Doc(), "", "  ")
+}
+
+// YAML 以 yaml 格式输出文档
+func (o *OpenAPI) YAML() ([]byte, error) {
+	b, err := json.Marshal(o.Doc())
+	if err != nil {
+		return nil, err
+	}
+	// json 也是 yaml，用 yaml.Node 保留字段顺序，再转为块格式输出
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func clearYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearYAMLStyle(c)
+	}
+}
+
+// Handler 输出文档，路径以 .yaml、.yml 结尾或 ?format=yaml 时输出 yaml，否则为 json
+//
+//	r.GET("/openapi.json", jgin.OpenAPIHandler())
+//	r.GET("/openapi.yaml", jgin.OpenAPIHandler())
+func (o *OpenAPI) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if c.Query("format") == "yaml" || strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+			b, err := o.YAML()
+			if err != nil {
+				ResultErr(nil, err, c)
+				return
+			}
+			c.Data(http.StatusOK, "application/yaml; charset=utf-8", b)
+			return
+		}
+		b, err := o.JSON()
+		if err != nil {
+			ResultErr(nil, err, c)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", b)
+	}
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// SetOpenAPIInfo 设置 DefaultOpenAPI 的基本信息
+func SetOpenAPIInfo(info OpenAPIInfo) {
+	DefaultOpenAPI.SetInfo(info)
+}
+
+// OpenAPIHandler 输出 DefaultOpenAPI 的文档
+func OpenAPIHandler() gin.HandlerFunc {
+	return DefaultOpenAPI.Handler()
+}
@@ -0,0 +1,104 @@
+package jgin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jgormtypes"
+	"github.com/xtulnx/jkit-go/jtypes"
+)
+
+type tApiOrderReq struct {
+	PageReq
+	Ids    JIDs   `form:"ids" doc:"订单号"`
+	Status int    `form:"status" binding:"required" example:"1"`
+	Token  string `header:"X-Token"`
+}
+
+type tApiOrder struct {
+	Id      uint                 `json:"id" example:"1"`
+	Price   jtypes.JPrice        `json:"price"`
+	Day     jgormtypes.JDate     `json:"day"`
+	Created *jgormtypes.JTime    `json:"created,omitempty"`
+	Updated jgormtypes.JDateTime `json:"updated"`
+	Items   []tApiOrder          `json:"items,omitempty"`
+	secret  string
+}
+
+type tApiOrderList struct {
+	PageResp
+	List []tApiOrder `json:"list"`
+}
+
+type tApiGetReq struct {
+	Id uint `uri:"id" json:"-"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	r := gin.New()
+	api := r.Group("/api")
+	GET(api, "/order", func(ctx context.Context, req *tApiOrderReq) (*tApiOrderList, error) {
+		return &tApiOrderList{}, nil
+	}, Summary("订单列表"), Tags("订单"))
+	POST(api, "/order/:id", func(ctx context.Context, req *tApiOrder) (tApiOrder, error) {
+		return *req, nil
+	})
+	DELETE(api, "/order/:id", func(ctx context.Context, req *tApiGetReq) (any, error) {
+		return nil, nil
+	})
+	r.GET("/openapi.json", OpenAPIHandler())
+	r.GET("/openapi.yaml", OpenAPIHandler())
+
+	doc := DefaultOpenAPI.Doc()
+	list := doc.Paths["/api/order"]["get"]
+	if list == nil || list.Summary != "订单列表" || len(list.Parameters) != 5 {
+		t.Fatalf("GET /api/order = %+v", list)
+	}
+	params := map[string]*APIParameter{}
+	for _, p := range list.Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	if p := params["query:ids"]; p == nil || p.Schema.Pattern == "" || p.Description != "订单号" {
+		t.Errorf("ids = %+v", p)
+	}
+	if p := params["query:status"]; p == nil || !p.Required || p.Schema.Example != int64(1) {
+		t.Errorf("status = %+v", p)
+	}
+	if params["header:X-Token"] == nil || params["query:page"] == nil {
+		t.Errorf("parameters = %v", params)
+	}
+
+	order := doc.Components.Schemas["jgin.tApiOrder"]
+	if order == nil || order.Properties["price"].Format != "decimal" || order.Properties["day"].Format != "date" ||
+		order.Properties["created"].Pattern == "" || order.Properties["updated"].Format != "" || order.Properties["updated"].Pattern == "" ||
+		order.Properties["items"].Items.Ref != "#/components/schemas/jgin.tApiOrder" ||
+		order.Properties["secret"] != nil {
+		t.Errorf("jgin.tApiOrder = %+v", order)
+	}
+	if s := doc.Components.Schemas["jgin.tApiOrderList"]; s == nil || s.Properties["totalPage"] == nil {
+		t.Errorf("jgin.tApiOrderList = %+v", s)
+	}
+	if op := doc.Paths["/api/order/{id}"]["delete"]; op == nil || len(op.Parameters) != 1 || op.Parameters[0].In != "path" {
+		t.Errorf("DELETE /api/order/{id} = %+v", op)
+	}
+	if op := doc.Paths["/api/order/{id}"]["post"]; op == nil || op.RequestBody == nil {
+		t.Errorf("POST /api/order/{id} = %+v", op)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var m map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &m); err != nil || m["openapi"] != OpenAPIVersion {
+		t.Errorf("openapi.json = %v, %s", err, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	if !strings.HasPrefix(w.Body.String(), "openapi: 3.0.3\n") || !strings.Contains(w.Body.String(), "/api/order/{id}:") {
+		t.Errorf("openapi.yaml = %s", w.Body.String())
+	}
+}
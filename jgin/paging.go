@@ -0,0 +1,113 @@
+package jgin
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+// 分页查询：统计总数、查询当前页，填充 PageResp
+//
+//	res, err := jgin.Paginate[model.Order](c.Request.Context(), db.Where("status = ?", 1).Order("id desc"), req.PageReq)
+//	jgin.ResultErr(res, err, c)
+
+// PageResult 分页结果，可以直接用于 OkWithData
+type PageResult[T any] struct {
+	PageResp
+	List []T `json:"list"`
+}
+
+// PageConfig 分页的默认值
+type PageConfig struct {
+	DefaultSize int `mapstructure:"defaultSize,omitempty" json:"defaultSize,omitempty" yaml:"defaultSize,omitempty" toml:"defaultSize,omitempty"` // 未指定分页大小时使用，默认 10
+	MaxSize     int `mapstructure:"maxSize,omitempty" json:"maxSize,omitempty" yaml:"maxSize,omitempty" toml:"maxSize,omitempty"`                 // 最大分页大小，超过时按最大值查询，默认 1000
+}
+
+var pageConfig = PageConfig{DefaultSize: 10, MaxSize: 1000}
+
+// SetPageConfig 设置分页的默认值
+func SetPageConfig(conf PageConfig) {
+	if conf.DefaultSize <= 0 {
+		conf.DefaultSize = 10
+	}
+	pageConfig = conf
+}
+
+type pageOptions struct {
+	PageConfig
+	concurrent bool
+}
+
+type PageOption func(o *pageOptions)
+
+// PageMaxSize 指定最大分页大小
+func PageMaxSize(n int) PageOption {
+	return func(o *pageOptions) { o.MaxSize = n }
+}
+
+// PageConcurrent 统计与查询同时进行，适合统计较慢的查询
+func PageConcurrent() PageOption {
+	return func(o *pageOptions) { o.concurrent = true }
+}
+
+// Paginate 分页查询 db，没有指定 Model、Table 时按 T 查询。页号超出范围时 List 为空
+func Paginate[T any](ctx context.Context, db *gorm.DB, req PageReq, opts ...PageOption) (*PageResult[T], error) {
+	o := pageOptions{PageConfig: pageConfig}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	req.FixPageSize(1, o.DefaultSize)
+	if o.MaxSize > 0 && req.PageSize > o.MaxSize {
+		req.PageSize = o.MaxSize
+	}
+
+	db = db.WithContext(ctx)
+	if db.Statement.Model == nil && db.Statement.Table == "" && db.Statement.TableExpr == nil {
+		db = db.Model(new(T))
+	}
+	// 统计、查询各自复制查询条件，互不影响
+	db = db.Session(&gorm.Session{})
+	res := &PageResult[T]{List: []T{}}
+	var total int64
+
+	if !o.concurrent {
+		if err := db.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		offset := res.SetPageSize(req.Page, req.PageSize, total)
+		if offset < 0 {
+			return res, nil
+		}
+		if err := db.Offset(offset).Limit(req.PageSize).Find(&res.List).Error; err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	var wg sync.WaitGroup
+	var errCount error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errCount = db.Count(&total).Error
+	}()
+	errFind := db.Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).Find(&res.List).Error
+	wg.Wait()
+	if errCount != nil {
+		return nil, errCount
+	}
+	if errFind != nil {
+		return nil, errFind
+	}
+	if res.SetPageSize(req.Page, req.PageSize, total) < 0 {
+		res.List = []T{}
+	}
+	return res, nil
+}
+
+// PaginateDao 分页查询 gen 的 dao，见 Paginate
+func PaginateDao[T any](ctx context.Context, dao gen.Dao, req PageReq, opts ...PageOption) (*PageResult[T], error) {
+	return Paginate[T](ctx, dao.(*gen.DO).UnderlyingDB(), req, opts...)
+}
@@ -0,0 +1,70 @@
+package jgin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+type tPageItem struct {
+	ID   uint `gorm:"primarykey"`
+	Name string
+}
+
+// openTestDB 内存中的 sqlite，插入 n 条记录
+func openTestDB(t *testing.T, name string, n int) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&tPageItem{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Where("1 = 1").Delete(&tPageItem{})
+	for i := 1; i <= n; i++ {
+		db.Create(&tPageItem{ID: uint(i), Name: fmt.Sprintf("n%02d", i)})
+	}
+	return db
+}
+
+func TestPaginate(t *testing.T) {
+	db := openTestDB(t, "paging", 25)
+	ctx := context.Background()
+
+	for _, concurrent := range []bool{false, true} {
+		var opts []PageOption
+		if concurrent {
+			opts = append(opts, PageConcurrent())
+		}
+		res, err := Paginate[tPageItem](ctx, db.Order("id desc"), PageReq{Page: 3, PageSize: 10}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Total != 25 || res.TotalPage != 3 || len(res.List) != 5 || res.List[0].ID != 5 {
+			t.Errorf("Paginate(%v) = %+v", concurrent, res)
+		}
+
+		res, err = Paginate[tPageItem](ctx, db, PageReq{Page: 4, PageSize: 10}, opts...)
+		if err != nil || len(res.List) != 0 || res.List == nil || res.Total != 25 {
+			t.Errorf("Paginate(%v) 超出范围 = %+v, %v", concurrent, res, err)
+		}
+	}
+
+	res, err := Paginate[tPageItem](ctx, db.Where("id > ?", 20), PageReq{PageSize: 100}, PageMaxSize(2))
+	if err != nil || res.Page != 1 || res.PageSize != 2 || res.Total != 5 || len(res.List) != 2 {
+		t.Errorf("Paginate(最大分页) = %+v, %v", res, err)
+	}
+
+	dao := &gen.DO{}
+	dao.UseDB(db.Model(&tPageItem{}))
+	res2, err := PaginateDao[tPageItem](ctx, dao.Where(field.NewString("", "name").Like("n1%")), PageReq{})
+	if err != nil || res2.Total != 10 || res2.PageSize != 10 {
+		t.Errorf("PaginateDao() = %+v, %v", res2, err)
+	}
+}
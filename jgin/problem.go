@@ -0,0 +1,191 @@
+package jgin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// RFC 7807 application/problem+json 格式的错误响应，成功的响应仍使用 Response。
+//
+//	{"type":"https://example.com/errors/550","title":"Bad Request","status":400,
+//	 "detail":"记录并不存在","instance":"/order/1","code":550}
+
+// RenderMode 响应的格式，RenderProblem 即使用 ProblemEnvelope，见 EnvelopeOf
+type RenderMode int
+
+const (
+	RenderDefault RenderMode = iota // {code,data,msg}
+	RenderProblem                   // 错误使用 RFC 7807 格式
+)
+
+// MIMEProblemJSON RFC 7807 的内容类型
+const MIMEProblemJSON = "application/problem+json"
+
+const ctxKeyRenderMode = "jgin.renderMode"
+
+var renderMode = RenderDefault
+
+// SetRenderMode 设置全局的响应格式
+func SetRenderMode(m RenderMode) {
+	renderMode = m
+}
+
+// UseRenderMode 中间件，设置路由组的响应格式
+//
+//	partner := r.Group("/partner", jgin.UseRenderMode(jgin.RenderProblem))
+func UseRenderMode(m RenderMode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ctxKeyRenderMode, m)
+	}
+}
+
+// RenderModeOf 请求使用的响应格式，路由组未设置时使用全局的
+func RenderModeOf(c *gin.Context) RenderMode {
+	if v, ok := c.Get(ctxKeyRenderMode); ok {
+		if m, ok := v.(RenderMode); ok {
+			return m
+		}
+	}
+	return renderMode
+}
+
+// ProblemConfig problem 响应的配置
+type ProblemConfig struct {
+	TypeBase string `mapstructure:"typeBase,omitempty" json:"typeBase,omitempty" yaml:"typeBase,omitempty" toml:"typeBase,omitempty"` // type 的前缀，后接错误码，如 https://example.com/errors/ ；为空时 type 为 about:blank
+}
+
+var problemConfig ProblemConfig
+
+// SetProblemConfig 设置 problem 响应的配置
+func SetProblemConfig(conf ProblemConfig) {
+	problemConfig = conf
+}
+
+// Problem RFC 7807 的错误信息，Extensions 与标准字段平铺输出
+type Problem struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"], m["title"], m["status"] = p.Type, p.Title, p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	type tProblem Problem
+	var p1 tProblem
+	if err := json.Unmarshal(data, &p1); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(m, k)
+	}
+	if len(m) > 0 {
+		p1.Extensions = m
+	}
+	*p = Problem(p1)
+	return nil
+}
+
+// response 把 problem 还原为 Response，扩展字段中的 code、fields 之外的作为 Details
+func (p *Problem) response() *Response {
+	r := &Response{Code: ERROR, Msg: p.Detail}
+	if r.Msg == "" {
+		r.Msg = p.Title
+	}
+	for k, v := range p.Extensions {
+		switch k {
+		case "code":
+			if f, ok := v.(float64); ok {
+				r.Code = int(f)
+			}
+		case "data":
+			r.Data = v
+		case "fields":
+			b, _ := json.Marshal(v)
+			_ = json.Unmarshal(b, &r.Fields)
+		default:
+			if r.Details == nil {
+				r.Details = make(map[string]any)
+			}
+			r.Details[k] = v
+		}
+	}
+	return r
+}
+
+// newProblem 按业务码生成 problem，错误的详细信息作为扩展字段，不覆盖标准字段
+func newProblem(c *gin.Context, status, code int, msg string, data any, details map[string]any, fields []jerrno.FieldError) Problem {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   msg,
+		Instance: c.Request.URL.Path,
+		Extensions: map[string]any{
+			"code": code,
+		},
+	}
+	if problemConfig.TypeBase != "" {
+		p.Type = problemConfig.TypeBase + strconv.Itoa(code)
+	}
+	for k, v := range details {
+		if _, ok := p.Extensions[k]; !ok {
+			p.Extensions[k] = v
+		}
+	}
+	if data != nil {
+		p.Extensions["data"] = data
+	}
+	if len(fields) > 0 {
+		p.Extensions["fields"] = fields
+	}
+	return p
+}
+
+// problemStatus 错误没有 http 状态码时，业务码本身是 4xx、5xx 的状态码则直接使用，
+// 否则按严重程度，SeverityError 为 500，其余为 400
+func problemStatus(code int, e error) int {
+	if code >= 400 && code < 600 && http.StatusText(code) != "" {
+		return code
+	}
+	if e != nil && jerrno.SeverityOf(e) >= jerrno.SeverityError {
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
+
+// renderProblem 即 ProblemEnvelope，成功时使用 Response
+func renderProblem(c *gin.Context, r *ResponseInfo) {
+	if r.Success {
+		renderDefault(c, r)
+		return
+	}
+	p := newProblem(c, r.Status(problemStatus(r.Code, r.Err)), r.Code, r.Msg, r.Data, r.Details, r.Fields)
+	c.Header("Content-Type", MIMEProblemJSON+"; charset=utf-8")
+	c.Render(p.Status, render.JSON{Data: p})
+}
@@ -0,0 +1,64 @@
+package jgin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+func TestProblem(t *testing.T) {
+	SetProblemConfig(ProblemConfig{TypeBase: "https://example.com/errors/"})
+	defer SetProblemConfig(ProblemConfig{})
+
+	r := gin.New()
+	g := r.Group("/partner", UseRenderMode(RenderProblem))
+	g.GET("/order", func(c *gin.Context) {
+		ResultErr(nil, jerrno.QueryNotFound.WithDetail("id", 1).WithFieldError("id", "不存在"), c)
+	})
+	g.GET("/ok", func(c *gin.Context) {
+		OkWithData("data", c)
+	})
+	r.GET("/order", func(c *gin.Context) {
+		ResultErr(nil, jerrno.QueryNotFound, c)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/partner/order", nil))
+	if w.Code != http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), MIMEProblemJSON) {
+		t.Fatalf("problem = %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	var p Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Type != "https://example.com/errors/550" || p.Status != 400 || p.Title != "Bad Request" ||
+		p.Detail != "记录并不存在" || p.Instance != "/partner/order" || p.Extensions["code"] != 550.0 || p.Extensions["id"] != 1.0 {
+		t.Errorf("problem = %s", w.Body.String())
+	}
+	if _, err := DecodeResponse(w.Result(), nil); !errors.Is(err, jerrno.QueryNotFound) || len(jerrno.FieldErrorsOf(err)) != 1 {
+		t.Errorf("DecodeResponse(problem) = %v", err)
+	}
+
+	for path, code := range map[string]int{"/partner/ok": SUCCESS, "/order": 550} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK || resp.Code != code {
+			t.Errorf("%s = %d %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	SetRenderMode(RenderProblem)
+	defer SetRenderMode(RenderDefault)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/order", nil))
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), MIMEProblemJSON) {
+		t.Errorf("全局 problem = %s", w.Body.String())
+	}
+}
@@ -0,0 +1,311 @@
+package jgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jgorm"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 列表接口的排序、过滤条件，字段按接口的白名单校验，不在白名单中时返回 jerrno.BadRequest
+//
+//	GET /order?sort=-created_at,name&filter=status:eq:1,name:like:abc
+//	POST /order {"sort": "-created_at,name", "filter": [{"field": "status", "op": "eq", "value": 1}]}
+//
+//	type OrderListReq struct {
+//		jgin.PageReq
+//		jgin.ListQuery
+//	}
+//
+//	var orderFields = jgin.ListFields{"created_at": q.Order.CreatedAt, "status": q.Order.Status, "name": "name"}
+//	if err := req.ListQuery.Apply(do, orderFields); err != nil { ... }
+
+// 过滤条件的操作符
+const (
+	FilterEq   = "eq"   // 等于，值为 null 时为 IS NULL
+	FilterNe   = "ne"   // 不等于，值为 null 时为 IS NOT NULL
+	FilterGt   = "gt"   // 大于
+	FilterGe   = "ge"   // 大于等于
+	FilterLt   = "lt"   // 小于
+	FilterLe   = "le"   // 小于等于
+	FilterLike = "like" // 包含
+	FilterIn   = "in"   // 在列表中，字符串形式时用 | 分隔，如 status:in:1|2
+)
+
+// SortField 排序字段
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// SortFields 排序，字符串形式为逗号分隔的字段，- 开头表示倒序，如 -created_at,name
+type SortFields []SortField
+
+// UnmarshalParam 用于 gin 绑定 query、form
+func (s *SortFields) UnmarshalParam(param string) error {
+	*s = nil
+	for _, v := range strings.Split(param, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		f := SortField{Field: v}
+		if v[0] == '-' || v[0] == '+' {
+			f.Field, f.Desc = strings.TrimSpace(v[1:]), v[0] == '-'
+		}
+		if f.Field == "" {
+			return jerrno.BadRequest.WithFieldError("sort", "排序格式有误: "+v)
+		}
+		*s = append(*s, f)
+	}
+	return nil
+}
+
+// UnmarshalJSON 支持字符串、字符串数组、对象数组
+func (s *SortFields) UnmarshalJSON(b []byte) error {
+	var str string
+	if json.Unmarshal(b, &str) == nil {
+		return s.UnmarshalParam(str)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	*s = nil
+	for _, item := range items {
+		var s1 SortFields
+		if json.Unmarshal(item, &str) == nil {
+			if err := s1.UnmarshalParam(str); err != nil {
+				return err
+			}
+		} else {
+			var f SortField
+			if err := json.Unmarshal(item, &f); err != nil {
+				return err
+			}
+			s1 = SortFields{f}
+		}
+		*s = append(*s, s1...)
+	}
+	return nil
+}
+
+func (s SortFields) String() string {
+	items := make([]string, len(s))
+	for i, f := range s {
+		if f.Desc {
+			items[i] = "-" + f.Field
+		} else {
+			items[i] = f.Field
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// FilterCond 过滤条件
+type FilterCond struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value any    `json:"value"`
+}
+
+// FilterConds 过滤条件，字符串形式为逗号分隔的 字段:操作符:值，如 status:eq:1,name:like:abc
+type FilterConds []FilterCond
+
+// UnmarshalParam 用于 gin 绑定 query、form
+func (s *FilterConds) UnmarshalParam(param string) error {
+	*s = nil
+	for _, v := range strings.Split(param, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		items := strings.SplitN(v, ":", 3)
+		if len(items) != 3 || items[0] == "" {
+			return jerrno.BadRequest.WithFieldError("filter", "过滤格式有误: "+v)
+		}
+		*s = append(*s, FilterCond{Field: items[0], Op: items[1], Value: items[2]})
+	}
+	return nil
+}
+
+// UnmarshalJSON 支持字符串、字符串数组、对象数组
+func (s *FilterConds) UnmarshalJSON(b []byte) error {
+	var str string
+	if json.Unmarshal(b, &str) == nil {
+		return s.UnmarshalParam(str)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	*s = nil
+	for _, item := range items {
+		var s1 FilterConds
+		if json.Unmarshal(item, &str) == nil {
+			if err := s1.UnmarshalParam(str); err != nil {
+				return err
+			}
+		} else {
+			var f FilterCond
+			if err := json.Unmarshal(item, &f); err != nil {
+				return err
+			}
+			s1 = FilterConds{f}
+		}
+		*s = append(*s, s1...)
+	}
+	return nil
+}
+
+func init() {
+	RegOpenAPISchema(SortFields{}, Schema{Type: "string", Description: "排序，- 开头表示倒序", Example: "-created_at,name"})
+	RegOpenAPISchema(FilterConds{}, Schema{Type: "string", Description: "过滤，字段:操作符:值，操作符有 eq ne gt ge lt le like in", Example: "status:eq:1,name:like:abc"})
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// ListFields 允许排序、过滤的字段，键为请求中的字段名，值为 field.Expr 或列名
+type ListFields map[string]any
+
+// column 字段对应的列，排序时只能使用列
+func (f ListFields) column(name string) (col any, ok bool) {
+	switch v := f[name].(type) {
+	case field.Expr:
+		return v.RawExpr(), true
+	case string:
+		return clause.Column{Name: v}, true
+	case clause.Column:
+		return v, true
+	}
+	return nil, false
+}
+
+// ListQuery 列表的排序、过滤条件
+type ListQuery struct {
+	Sort   SortFields  `form:"sort" json:"sort,omitempty" query:"sort"`       // 排序，如 -created_at,name
+	Filter FilterConds `form:"filter" json:"filter,omitempty" query:"filter"` // 过滤，如 status:eq:1,name:like:abc
+}
+
+// Clauses 按白名单转成查询条件与排序
+func (q *ListQuery) Clauses(fields ListFields) ([]clause.Expression, error) {
+	var conds []clause.Expression
+	for _, f := range q.Filter {
+		col, ok := fields.column(f.Field)
+		if !ok {
+			return nil, jerrno.BadRequest.WithFieldError("filter", "不支持过滤的字段: "+f.Field)
+		}
+		cond, err := filterExpr(col, f)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	if len(q.Sort) > 0 {
+		orderBy := clause.OrderBy{}
+		for _, s := range q.Sort {
+			col, ok := fields.column(s.Field)
+			c1, isCol := col.(clause.Column)
+			if !ok || !isCol {
+				return nil, jerrno.BadRequest.WithFieldError("sort", "不支持排序的字段: "+s.Field)
+			}
+			orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: c1, Desc: s.Desc})
+		}
+		conds = append(conds, orderBy)
+	}
+	return conds, nil
+}
+
+// Apply 按白名单应用到 dao，见 jgorm.DbClause
+func (q *ListQuery) Apply(dao gen.Dao, fields ListFields) error {
+	conds, err := q.Clauses(fields)
+	if err != nil {
+		return err
+	}
+	jgorm.DbClause(dao, conds)
+	return nil
+}
+
+// ApplyDB 按白名单应用到 gorm.DB
+func (q *ListQuery) ApplyDB(db *gorm.DB, fields ListFields) (*gorm.DB, error) {
+	conds, err := q.Clauses(fields)
+	if err != nil {
+		return db, err
+	}
+	if len(conds) == 0 {
+		return db, nil
+	}
+	return db.Clauses(conds...), nil
+}
+
+func filterExpr(col any, f FilterCond) (clause.Expression, error) {
+	v := f.Value
+	op := strings.ToLower(f.Op)
+	if op != FilterIn && !isFilterScalar(v) {
+		return nil, jerrno.BadRequest.WithFieldError("filter", f.Field+" 的值不能是对象或数组")
+	}
+	switch op {
+	case FilterEq:
+		if v == nil {
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{col}}, nil
+		}
+		return clause.Expr{SQL: "? = ?", Vars: []any{col, v}}, nil
+	case FilterNe:
+		if v == nil {
+			return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{col}}, nil
+		}
+		return clause.Expr{SQL: "? <> ?", Vars: []any{col, v}}, nil
+	case FilterGt:
+		return clause.Expr{SQL: "? > ?", Vars: []any{col, v}}, nil
+	case FilterGe:
+		return clause.Expr{SQL: "? >= ?", Vars: []any{col, v}}, nil
+	case FilterLt:
+		return clause.Expr{SQL: "? < ?", Vars: []any{col, v}}, nil
+	case FilterLe:
+		return clause.Expr{SQL: "? <= ?", Vars: []any{col, v}}, nil
+	case FilterLike:
+		// 转义通配符，转义符作为参数传入，避免 mysql 把字面量中的 \ 当作转义
+		return clause.Expr{SQL: "? LIKE ? ESCAPE ?", Vars: []any{col, "%" + likeEscaper.Replace(fmt.Sprint(v)) + "%", `\`}}, nil
+	case FilterIn:
+		var values []any
+		switch v1 := v.(type) {
+		case string:
+			for _, s := range strings.Split(v1, "|") {
+				values = append(values, s)
+			}
+		case []any:
+			for _, v2 := range v1 {
+				if !isFilterScalar(v2) {
+					return nil, jerrno.BadRequest.WithFieldError("filter", f.Field+" 的值不能是对象或数组")
+				}
+			}
+			values = v1
+		default:
+			if !isFilterScalar(v) {
+				return nil, jerrno.BadRequest.WithFieldError("filter", f.Field+" 的值不能是对象或数组")
+			}
+			values = []any{v}
+		}
+		return clause.Expr{SQL: "? IN ?", Vars: []any{col, values}}, nil
+	}
+	return nil, jerrno.BadRequest.WithFieldError("filter", "不支持的操作符: "+f.Op)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// isFilterScalar 值不是对象或数组，json 中的对象、数组解析为 map、slice
+func isFilterScalar(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package jgin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+)
+
+type tListReq struct {
+	PageReq
+	ListQuery
+}
+
+func bindListReq(t *testing.T, req *http.Request) (*tListReq, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	var r tListReq
+	return &r, GinMustBind(c, &r)
+}
+
+func TestListQueryBind(t *testing.T) {
+	r, err := bindListReq(t, httptest.NewRequest(http.MethodGet, "/?sort=-created_at,+name&filter=status:eq:1,name:like:a:b,id:in:1|2", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Sort.String() != "-created_at,name" || len(r.Filter) != 3 || r.Filter[1].Value != "a:b" {
+		t.Errorf("query = %+v", r.ListQuery)
+	}
+
+	body := `{"sort":["-id",{"field":"name"}],"filter":[{"field":"status","op":"eq","value":1},"name:like:abc"]}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if r, err = bindListReq(t, req); err != nil {
+		t.Fatal(err)
+	}
+	if r.Sort.String() != "-id,name" || len(r.Filter) != 2 || r.Filter[0].Value != 1.0 || r.Filter[1].Value != "abc" {
+		t.Errorf("json = %+v", r.ListQuery)
+	}
+
+	if _, err = bindListReq(t, httptest.NewRequest(http.MethodGet, "/?filter=status", nil)); !errors.Is(err, jerrno.BadRequest) {
+		t.Errorf("格式有误 = %v", err)
+	}
+}
+
+func TestListQueryApply(t *testing.T) {
+	db := openTestDB(t, "query", 25)
+	fields := ListFields{"id": field.NewUint("", "id"), "name": "name"}
+
+	q := ListQuery{
+		Sort:   SortFields{{Field: "id", Desc: true}},
+		Filter: FilterConds{{Field: "name", Op: FilterLike, Value: "n1"}, {Field: "id", Op: FilterNe, Value: "12"}},
+	}
+	dao := &gen.DO{}
+	dao.UseDB(db.Model(&tPageItem{}))
+	if err := q.Apply(dao, fields); err != nil {
+		t.Fatal(err)
+	}
+	res, err := PaginateDao[tPageItem](context.Background(), dao, PageReq{PageSize: 3})
+	if err != nil || res.Total != 9 || len(res.List) != 3 || res.List[0].ID != 19 || res.List[1].ID != 18 {
+		t.Errorf("Apply() = %+v, %v", res, err)
+	}
+
+	q = ListQuery{
+		Sort:   SortFields{{Field: "name"}},
+		Filter: FilterConds{{Field: "id", Op: FilterIn, Value: "3|1|2"}, {Field: "name", Op: FilterNe, Value: nil}},
+	}
+	db1, err := q.ApplyDB(db, fields)
+	var items []tPageItem
+	if err == nil {
+		err = db1.Find(&items).Error
+	}
+	if err != nil || len(items) != 3 || items[0].ID != 1 || items[2].ID != 3 {
+		t.Errorf("ApplyDB() = %+v, %v", items, err)
+	}
+
+	// 通配符按字面匹配
+	for _, like := range []string{"%", "n_", `\`} {
+		q = ListQuery{Filter: FilterConds{{Field: "name", Op: FilterLike, Value: like}}}
+		var n int64
+		if db1, err = q.ApplyDB(db.Model(&tPageItem{}), fields); err == nil {
+			err = db1.Count(&n).Error
+		}
+		if err != nil || n != 0 {
+			t.Errorf("ApplyDB(like %q) = %d, %v", like, n, err)
+		}
+	}
+
+	for _, q := range []ListQuery{
+		{Sort: SortFields{{Field: "created_at"}}},
+		{Filter: FilterConds{{Field: "id", Op: FilterGt, Value: map[string]any{"$ne": 1}}}},
+		{Filter: FilterConds{{Field: "name", Op: FilterLike, Value: []any{"a"}}}},
+		{Filter: FilterConds{{Field: "id", Op: FilterIn, Value: []any{1, []any{2}}}}},
+		{Filter: FilterConds{{Field: "password", Op: FilterEq, Value: "1"}}},
+		{Filter: FilterConds{{Field: "id", Op: "regexp", Value: "1"}}},
+	} {
+		_, err = q.Clauses(fields)
+		if !errors.Is(err, jerrno.BadRequest) || len(jerrno.FieldErrorsOf(err)) != 1 {
+			t.Errorf("Clauses(%+v) = %v", q, err)
+		}
+	}
+}
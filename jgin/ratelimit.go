@@ -0,0 +1,319 @@
+package jgin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// 限流：令牌桶、滑动窗口两种算法，按客户端 ip、用户、路由或自定义的键计数，
+// 超过限制时返回 jerrno.TooManyRequests，并设置 RateLimit-* 响应头。
+//
+//	r.Use(jgin.RateLimit(jgin.RateLimitConfig{Limit: 10, PeriodSec: 1, KeyBy: "ip"}))
+
+const (
+	RateLimitToken  = "token"  // 令牌桶，允许 Burst 个突发请求
+	RateLimitWindow = "window" // 滑动窗口，按前后两个窗口加权估算
+)
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Name      string `mapstructure:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty"`                     // 名称，多个规则共用存储时用于区分
+	Algorithm string `mapstructure:"algorithm,omitempty" json:"algorithm,omitempty" yaml:"algorithm,omitempty" toml:"algorithm,omitempty"` // token（默认）、window
+	KeyBy     string `mapstructure:"keyBy,omitempty" json:"keyBy,omitempty" yaml:"keyBy,omitempty" toml:"keyBy,omitempty"`                 // ip（默认）、user、route，可以用逗号组合，如 route,ip
+	Limit     int    `mapstructure:"limit,omitempty" json:"limit,omitempty" yaml:"limit,omitempty" toml:"limit,omitempty"`                 // 每个周期允许的请求数，0 表示不限流
+	PeriodSec int    `mapstructure:"periodSec,omitempty" json:"periodSec,omitempty" yaml:"periodSec,omitempty" toml:"periodSec,omitempty"` // 周期（秒），默认 1
+	Burst     int    `mapstructure:"burst,omitempty" json:"burst,omitempty" yaml:"burst,omitempty" toml:"burst,omitempty"`                 // 令牌桶的容量，默认等于 Limit
+}
+
+func (r *RateLimitConfig) FixDefault() {
+	if r.Algorithm == "" {
+		r.Algorithm = RateLimitToken
+	}
+	if r.KeyBy == "" {
+		r.KeyBy = "ip"
+	}
+	if r.PeriodSec <= 0 {
+		r.PeriodSec = 1
+	}
+	if r.Burst <= 0 {
+		r.Burst = r.Limit
+	}
+}
+
+// Period 周期
+func (r *RateLimitConfig) Period() time.Duration {
+	return time.Duration(r.PeriodSec) * time.Second
+}
+
+// RateLimitResult 一次限流检查的结果
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int           // 周期内允许的请求数
+	Remaining  int           // 剩余的请求数
+	Reset      time.Duration // 多久后恢复到满额
+	RetryAfter time.Duration // 被限流时，多久后可以重试
+}
+
+// RateLimitStore 限流状态的存储，默认在内存中，多实例部署时可以换成 redis 等
+type RateLimitStore interface {
+	// Take 按 key 消耗一次请求
+	Take(ctx context.Context, key string, conf RateLimitConfig, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitKeyFunc 计算限流的键，返回空时不限流
+type RateLimitKeyFunc func(c *gin.Context) string
+
+type rateLimitOptions struct {
+	store RateLimitStore
+	key   RateLimitKeyFunc
+}
+
+type RateLimitOption func(o *rateLimitOptions)
+
+// WithRateLimitStore 使用自定义的存储
+func WithRateLimitStore(s RateLimitStore) RateLimitOption {
+	return func(o *rateLimitOptions) { o.store = s }
+}
+
+// WithRateLimitKey 使用自定义的键，替代 KeyBy
+func WithRateLimitKey(fn RateLimitKeyFunc) RateLimitOption {
+	return func(o *rateLimitOptions) { o.key = fn }
+}
+
+// RateLimit 限流中间件，存储出错时不限流，错误记录到 c.Errors
+func RateLimit(conf RateLimitConfig, opts ...RateLimitOption) gin.HandlerFunc {
+	conf.FixDefault()
+	o := rateLimitOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.store == nil {
+		o.store = NewMemoryRateLimitStore()
+	}
+	if o.key == nil {
+		o.key = rateLimitKeyBy(conf.KeyBy)
+	}
+	prefix := conf.Name + ":"
+
+	return func(c *gin.Context) {
+		if conf.Limit <= 0 {
+			return
+		}
+		key := o.key(c)
+		if key == "" {
+			return
+		}
+		res, err := o.store.Take(c.Request.Context(), prefix+key, conf, time.Now())
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		h := c.Writer.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(max(res.Remaining, 0)))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			c.Abort()
+			ResultErr(nil, jerrno.TooManyRequests, c)
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// rateLimitKeyBy 按 ip、user、route 组合键，没有登录的用户按 ip。
+// 有不支持的部分时 panic，避免配置写错后所有请求都不限流
+func rateLimitKeyBy(keyBy string) RateLimitKeyFunc {
+	var fns []RateLimitKeyFunc
+	for _, k := range strings.Split(keyBy, ",") {
+		switch k = strings.TrimSpace(k); k {
+		case "ip":
+			fns = append(fns, KeyByIP)
+		case "user":
+			fns = append(fns, KeyByUser)
+		case "route":
+			fns = append(fns, KeyByRoute)
+		default:
+			panic(fmt.Sprintf("jgin: 限流的 keyBy %q 不支持 %q，可选 ip、user、route", keyBy, k))
+		}
+	}
+	if len(fns) == 1 {
+		return fns[0]
+	}
+	return func(c *gin.Context) string {
+		keys := make([]string, len(fns))
+		for i, fn := range fns {
+			keys[i] = fn(c)
+		}
+		return strings.Join(keys, "|")
+	}
+}
+
+// KeyByIP 按客户端 ip
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser 按用户，没有登录时按客户端 ip，见 SetUserID
+func KeyByUser(c *gin.Context) string {
+	if id := UserIDOf(c); id != "" {
+		return "user:" + id
+	}
+	return KeyByIP(c)
+}
+
+// KeyByRoute 按路由，未匹配路由时按路径
+func KeyByRoute(c *gin.Context) string {
+	if r := c.FullPath(); r != "" {
+		return "route:" + c.Request.Method + " " + r
+	}
+	return "route:" + c.Request.Method + " " + c.Request.URL.Path
+}
+
+const ctxKeyUserID = "jgin.userId"
+
+// SetUserID 设置当前请求的用户，用于按用户限流等
+func SetUserID(c *gin.Context, id string) {
+	c.Set(ctxKeyUserID, id)
+}
+
+// UserIDOf 当前请求的用户，未登录时为空
+func UserIDOf(c *gin.Context) string {
+	return c.GetString(ctxKeyUserID)
+}
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// MemoryRateLimitStore 内存中的限流状态，定期清理已经恢复满额的键
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	items     map[string]*rateLimitItem
+	lastSweep time.Time
+}
+
+type rateLimitItem struct {
+	expire time.Time // 超过这个时间没有访问，状态已经恢复满额，可以清理
+
+	// 令牌桶
+	tokens   float64
+	refilled time.Time
+
+	// 滑动窗口
+	windowStart     time.Time
+	prevCnt, curCnt int
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{items: make(map[string]*rateLimitItem)}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, conf RateLimitConfig, now time.Time) (RateLimitResult, error) {
+	conf.FixDefault()
+	if conf.Limit <= 0 {
+		// 不限流
+		return RateLimitResult{Allowed: true}, nil
+	}
+	period := conf.Period()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	item, ok := s.items[key]
+	if !ok {
+		item = &rateLimitItem{tokens: float64(conf.Burst), refilled: now, windowStart: now}
+		s.items[key] = item
+	}
+	// 令牌桶最多 Burst/Limit 个周期补满，滑动窗口两个周期后不再有计数；
+	// 按各自的规则计算，多个规则共用存储时互不影响
+	item.expire = now.Add(period * time.Duration(conf.Burst/conf.Limit+2))
+	if conf.Algorithm == RateLimitWindow {
+		return item.takeWindow(conf.Limit, period, now), nil
+	}
+	return item.takeToken(conf.Limit, conf.Burst, period, now), nil
+}
+
+// rateLimitSweepInterval 清理的间隔
+const rateLimitSweepInterval = time.Minute
+
+// sweep 清理已经过期的键
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for k, v := range s.items {
+		if now.After(v.expire) {
+			delete(s.items, k)
+		}
+	}
+}
+
+// takeToken 令牌桶，每个周期补充 limit 个令牌，最多 burst 个
+func (t *rateLimitItem) takeToken(limit, burst int, period time.Duration, now time.Time) RateLimitResult {
+	rate := float64(limit) / period.Seconds() // 每秒补充的令牌
+	elapsed := now.Sub(t.refilled).Seconds()
+	t.refilled = now
+	t.tokens = math.Min(float64(burst), t.tokens+elapsed*rate)
+
+	res := RateLimitResult{Limit: burst}
+	if t.tokens >= 1 {
+		t.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - t.tokens) / rate)
+	}
+	res.Remaining = int(t.tokens)
+	res.Reset = secondsToDuration((float64(burst) - t.tokens) / rate)
+	return res
+}
+
+// takeWindow 滑动窗口，当前窗口的计数加上前一个窗口按剩余比例折算的计数
+func (t *rateLimitItem) takeWindow(limit int, period time.Duration, now time.Time) RateLimitResult {
+	if d := now.Sub(t.windowStart); d >= period {
+		n := d / period
+		if n == 1 {
+			t.prevCnt = t.curCnt
+		} else {
+			t.prevCnt = 0
+		}
+		t.curCnt = 0
+		t.windowStart = t.windowStart.Add(n * period)
+	}
+	elapsed := now.Sub(t.windowStart)
+	weight := 1 - float64(elapsed)/float64(period)
+	count := float64(t.prevCnt)*weight + float64(t.curCnt)
+
+	res := RateLimitResult{Limit: limit, Reset: period - elapsed}
+	if count+1 <= float64(limit) {
+		t.curCnt++
+		res.Allowed = true
+		res.Remaining = int(float64(limit) - count - 1)
+		return res
+	}
+	res.RetryAfter = period - elapsed
+	if t.curCnt < limit && t.prevCnt > 0 {
+		// 前一个窗口的计数随时间减少，算出可以再放行一个请求的时间
+		need := float64(t.prevCnt - (limit - t.curCnt - 1))
+		wait := time.Duration(need/float64(t.prevCnt)*float64(period)) - elapsed
+		if wait > 0 && wait < res.RetryAfter {
+			res.RetryAfter = wait
+		}
+	}
+	return res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,99 @@
+package jgin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	s := NewMemoryRateLimitStore()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	token := RateLimitConfig{Limit: 2, PeriodSec: 1, Burst: 3}
+	for i, want := range []bool{true, true, true, false} {
+		if res, _ := s.Take(ctx, "t", token, now); res.Allowed != want || res.Remaining != 2-i && want {
+			t.Errorf("token[%d] = %+v", i, res)
+		}
+	}
+	if res, _ := s.Take(ctx, "t", token, now.Add(500*time.Millisecond)); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("token 补充 = %+v", res)
+	}
+	if res, _ := s.Take(ctx, "t", token, now.Add(500*time.Millisecond)); res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("token 限流 = %+v", res)
+	}
+
+	window := RateLimitConfig{Algorithm: RateLimitWindow, Limit: 4, PeriodSec: 10}
+	for i := 0; i < 4; i++ {
+		if res, _ := s.Take(ctx, "w", window, now); !res.Allowed || res.Remaining != 3-i {
+			t.Errorf("window[%d] = %+v", i, res)
+		}
+	}
+	if res, _ := s.Take(ctx, "w", window, now.Add(5*time.Second)); res.Allowed || res.RetryAfter != 5*time.Second {
+		t.Errorf("window 限流 = %+v", res)
+	}
+	// 下一个窗口过了一半，前一个窗口的 4 次折算为 2 次
+	for i, want := range []bool{true, true, false} {
+		if res, _ := s.Take(ctx, "w", window, now.Add(15*time.Second)); res.Allowed != want {
+			t.Errorf("window 滑动[%d] = %+v", i, res)
+		}
+	}
+
+	// Limit 为 0 时不限流
+	if res, err := s.Take(ctx, "z", RateLimitConfig{}, now); err != nil || !res.Allowed {
+		t.Errorf("Take(Limit=0) = %+v, %v", res, err)
+	}
+
+	// 周期短的规则触发清理时，不影响周期长的规则
+	hour := RateLimitConfig{Limit: 1, PeriodSec: 3600}
+	s.Take(ctx, "h", hour, now)
+	s.Take(ctx, "s", RateLimitConfig{Limit: 1}, now.Add(10*time.Minute))
+	if res, _ := s.Take(ctx, "h", hour, now.Add(10*time.Minute)); res.Allowed {
+		t.Errorf("清理了未过期的键 = %+v", res)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		SetUserID(c, c.GetHeader("X-User"))
+	}, RateLimit(RateLimitConfig{Limit: 1, PeriodSec: 60, KeyBy: "user,route"}))
+	r.GET("/a", Ok)
+	r.GET("/b", Ok)
+
+	do := func(path, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	if w := do("/a", "u1"); w.Body.String() != `{"code":0,"msg":"操作成功"}` || w.Header().Get("RateLimit-Limit") != "1" || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("第一次 = %s %v", w.Body.String(), w.Header())
+	}
+	if w := do("/a", "u1"); w.Body.String() != `{"code":429,"msg":"访问太快，请稍候再试"}` || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("限流 = %s %v", w.Body.String(), w.Header())
+	}
+	if w := do("/b", "u1"); w.Header().Get("Retry-After") != "" {
+		t.Errorf("其他路由 = %s", w.Body.String())
+	}
+	if w := do("/a", "u2"); w.Header().Get("Retry-After") != "" {
+		t.Errorf("其他用户 = %s", w.Body.String())
+	}
+
+	for _, keyBy := range []string{"ipp", "ip,header"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RateLimit(KeyBy=%q) 没有 panic", keyBy)
+				}
+			}()
+			RateLimit(RateLimitConfig{Limit: 1, KeyBy: keyBy})
+		}()
+	}
+}
@@ -0,0 +1,96 @@
+package jgin
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"github.com/xtulnx/jkit-go/jlog"
+	"go.uber.org/zap"
+)
+
+// Recovery 捕获 panic 并按 ResultErr 返回，替代 gin.Recovery。
+//
+//   - panic 的值带有 jerrno.ErrEx（见 Throw）时，按该错误返回，不记录 panic 日志，用于业务提前结束处理
+//   - 其他的 panic 返回 jerrno.InternalServerError，并记录调用栈与请求信息
+//   - 客户端已断开连接时只记录日志；http.ErrAbortHandler 继续 panic，由 net/http 处理
+//
+// l 为空时使用 zap.L()
+func Recovery(l *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+			err, _ := r.(error)
+			if _, ok := jerrno.FromError(err); ok {
+				renderRecovered(c, err)
+				return
+			}
+			if err == nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+			logger := l
+			if logger == nil {
+				logger = zap.L()
+			}
+			jlog.LogErr(jlog.ForContext(c.Request.Context(), logger), "请求 panic", err,
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.String("ip", c.ClientIP()),
+				zap.String("stack", string(debug.Stack())),
+			)
+			if isBrokenPipe(err) {
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+			renderRecovered(c, jerrno.InternalServerError.WithError(err))
+		}()
+		c.Next()
+	}
+}
+
+// Throw 以错误提前结束处理，由 Recovery 捕获后按 ResultErr 返回
+//
+//	if order == nil {
+//		jgin.Throw(jerrno.QueryNotFound)
+//	}
+func Throw(e jerrno.ErrEx) {
+	panic(e)
+}
+
+// renderRecovered 已经输出了响应时不再输出
+func renderRecovered(c *gin.Context, e error) {
+	c.Abort()
+	if c.Writer.Written() {
+		c.Set(ctxKeyRespErr, e)
+		return
+	}
+	ResultErr(nil, e, c)
+}
+
+// isBrokenPipe 客户端断开连接
+func isBrokenPipe(err error) bool {
+	var ne *net.OpError
+	if !errors.As(err, &ne) {
+		return false
+	}
+	var se *os.SyscallError
+	if errors.As(ne, &se) {
+		s := strings.ToLower(se.Error())
+		return strings.Contains(s, "broken pipe") || strings.Contains(s, "connection reset by peer")
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package jgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecovery(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	r := gin.New()
+	r.Use(Recovery(zap.New(core)))
+	r.GET("/throw", func(c *gin.Context) {
+		Throw(jerrno.Forbidden)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		var m map[string]int
+		m["a"] = 1
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/throw", nil))
+	if w.Code != http.StatusOK || w.Body.String() != `{"code":403,"msg":"权限不足"}` {
+		t.Errorf("Throw() = %d %s", w.Code, w.Body.String())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Throw() 不应记录日志: %v", logs.All())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusOK || w.Body.String() != `{"code":500,"msg":"服务器错误"}` {
+		t.Errorf("panic = %d %s", w.Code, w.Body.String())
+	}
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].ContextMap()["route"] != "/panic" || entries[0].ContextMap()["stack"] == "" {
+		t.Errorf("panic 日志 = %v", entries)
+	}
+}
@@ -0,0 +1,93 @@
+package jgin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jlog"
+)
+
+// 请求 ID：优先使用请求头 X-Request-ID，其次是 W3C traceparent 的 trace-id，都没有时生成。
+// 保存到请求的 context（ReqWithCtx 可以获取，jlog.ForContext、jgorm 的 sql 日志会带上），并在响应头中返回
+
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceparent = "traceparent"
+)
+
+const ctxKeyRequestID = "jgin.requestId"
+
+// maxRequestIDLen 请求头中的请求 ID 超过该长度时忽略
+const maxRequestIDLen = 128
+
+// RequestID 中间件，处理请求 ID，应放在其他中间件之前
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceID, _ := ParseTraceparent(c.GetHeader(HeaderTraceparent))
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID(id) {
+			id = traceID
+		}
+		if id == "" {
+			id = NewRequestID()
+		}
+		ctx = jlog.WithRequestID(ctx, id)
+		if traceID != "" {
+			ctx = jlog.WithTraceID(ctx, traceID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(ctxKeyRequestID, id)
+		c.Header(HeaderRequestID, id)
+		c.Next()
+	}
+}
+
+// RequestIDOf 请求 ID，没有使用 RequestID 中间件时为空
+func RequestIDOf(c *gin.Context) string {
+	return c.GetString(ctxKeyRequestID)
+}
+
+// NewRequestID 生成请求 ID，32 位十六进制
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ParseTraceparent 解析 W3C traceparent，如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(s string) (traceID, spanID string) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", ""
+	}
+	if !isLowerHex(parts[1]) || !isLowerHex(parts[2]) ||
+		parts[1] == "00000000000000000000000000000000" || parts[2] == "0000000000000000" {
+		return "", ""
+	}
+	return parts[1], parts[2]
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// validRequestID 只接受可见的 ascii 字符，避免日志注入
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package jgin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jlog"
+)
+
+type tReqIDReq struct {
+	ReqWithCtx
+}
+
+func TestRequestID(t *testing.T) {
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/", Handle(func(ctx context.Context, req *tReqIDReq) ([]string, error) {
+		return []string{jlog.RequestID(req.GetCtx()), jlog.TraceID(ctx)}, nil
+	}))
+
+	cases := []struct {
+		reqID, traceparent string
+		wantID, wantTrace  string
+	}{
+		{"abc-123", "", "abc-123", ""},
+		{"", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"abc-123", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "abc-123", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"bad\nid", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "", ""},
+	}
+	for _, v := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if v.reqID != "" {
+			req.Header.Set(HeaderRequestID, v.reqID)
+		}
+		if v.traceparent != "" {
+			req.Header.Set(HeaderTraceparent, v.traceparent)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		id := w.Header().Get(HeaderRequestID)
+		if v.wantID == "" {
+			if len(id) != 32 {
+				t.Errorf("%q 生成的请求 ID = %q", v.reqID, id)
+			}
+			v.wantID = id
+		}
+		want := `{"code":0,"data":["` + v.wantID + `","` + v.wantTrace + `"],"msg":"操作成功"}`
+		if id != v.wantID || w.Body.String() != want {
+			t.Errorf("%q %q = %s %s", v.reqID, v.traceparent, id, w.Body.String())
+		}
+	}
+}
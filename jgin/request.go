@@ -57,3 +57,17 @@ func (R *ReqWithCtx) SetCtxValue(k, v interface{}) {
 func (R *ReqWithCtx) GetCtxValue(k interface{}) interface{} {
 	return R.ctx.Value(k)
 }
+
+// -o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-o-
+
+// ReqWithUser 当前登录的用户，未登录时为 nil
+type ReqWithUser struct {
+	user *User
+}
+
+func (R *ReqWithUser) SetUser(u *User) {
+	R.user = u
+}
+func (R *ReqWithUser) GetUser() *User {
+	return R.user
+}
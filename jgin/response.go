@@ -1,14 +1,54 @@
 package jgin
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
 	"net/http"
+	"strings"
 )
 
 type Response struct {
-	Code int         `json:"code"`
-	Data interface{} `json:"data,omitempty"`
-	Msg  string      `json:"msg"`
+	Code    int                 `json:"code"`
+	Data    interface{}         `json:"data,omitempty"`
+	Msg     string              `json:"msg"`
+	Details map[string]any      `json:"details,omitempty"` // 错误的详细信息
+	Fields  []jerrno.FieldError `json:"fields,omitempty"`  // 字段错误，便于客户端标记表单字段
+}
+
+// Err 把其他服务返回的 Response 还原为错误，成功时返回 nil。
+// httpCode 为响应的 http 状态码，错误码已登记时返回登记的错误，可以用 errors.Is 比较，见 jerrno.FromCode
+func (r *Response) Err(httpCode int) error {
+	if r.Code == SUCCESS {
+		return nil
+	}
+	if httpCode == http.StatusOK {
+		httpCode = 0
+	}
+	return jerrno.WithExtra(jerrno.FromCode(r.Code, httpCode, r.Msg), nil, r.Details, r.Fields)
+}
+
+// DecodeResponse 解析其他服务返回的响应，data 非空时把 Data 解析到 data，
+// 业务码不是 SUCCESS 时返回对应的错误，见 Response.Err。也支持 problem 格式的响应
+//
+//	var user User
+//	_, err := jgin.DecodeResponse(resp, &user)
+//	if errors.Is(err, jerrno.QueryNotFound) { ... }
+func DecodeResponse(resp *http.Response, data interface{}) (*Response, error) {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), MIMEProblemJSON) {
+		var p Problem
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			return nil, fmt.Errorf("jgin: 解析响应失败, http 状态码 %d: %w", resp.StatusCode, err)
+		}
+		r := p.response()
+		return r, r.Err(resp.StatusCode)
+	}
+	r := &Response{Data: data}
+	if err := json.NewDecoder(resp.Body).Decode(r); err != nil {
+		return nil, fmt.Errorf("jgin: 解析响应失败, http 状态码 %d: %w", resp.StatusCode, err)
+	}
+	return r, r.Err(resp.StatusCode)
 }
 
 type ErrorWithCode interface {
@@ -23,28 +63,59 @@ const (
 	SUCCESS = 0
 )
 
+// 记录在 gin.Context 中的响应信息，用于日志等中间件
+const (
+	ctxKeyRespCode = "jgin.respCode"
+	ctxKeyRespErr  = "jgin.respErr"
+)
+
+// ResponseCode 已经返回的业务码
+func ResponseCode(c *gin.Context) (int, bool) {
+	if v, ok := c.Get(ctxKeyRespCode); ok {
+		code, ok := v.(int)
+		return code, ok
+	}
+	return 0, false
+}
+
+// ResponseError ResultErr 返回的错误（已经过 jerrno.Translate 转换）
+func ResponseError(c *gin.Context) error {
+	if v, ok := c.Get(ctxKeyRespErr); ok {
+		err, _ := v.(error)
+		return err
+	}
+	return nil
+}
+
+// Result 返回业务码、数据、信息，按请求的 Envelope 输出，见 EnvelopeOf
 func Result(code int, data interface{}, msg string, c *gin.Context) {
-	c.JSON(http.StatusOK, Response{code, data, msg})
+	c.Set(ctxKeyRespCode, code)
+	EnvelopeOf(c).Render(c, &ResponseInfo{Success: code == SUCCESS, Code: code, Data: data, Msg: msg})
 }
 
-// ResultErr 处理错误，如果错误为nil，则返回成功，否则按照错误类型返回
+// ResultErr 处理错误，如果错误为nil，则返回成功，否则按照错误类型返回，按请求的 Envelope 输出，见 EnvelopeOf。
+// 先用 jerrno.Translate 转换其他包的错误，错误信息按请求的语言本地化，见 RequestLocales。
+// gorm 等错误的转换需要自行引用 jerrno/translator：import _ "github.com/xtulnx/jkit-go/jerrno/translator"
 func ResultErr(data interface{}, e error, c *gin.Context) {
-	var httpCode = http.StatusOK
-	var code = ERROR
-	var msg = "内部错误"
+	r := &ResponseInfo{Code: ERROR, Msg: "内部错误", Data: data}
 
 	if e == nil {
-		code, msg = SUCCESS, "操作成功"
+		r.Success, r.Code, r.Msg = true, SUCCESS, "操作成功"
 	} else {
-		msg = e.Error()
-		if ex, ok := e.(ErrorWithCode); ok {
-			code = ex.ErrorCode()
+		e = jerrno.Translate(e)
+		c.Set(ctxKeyRespErr, e)
+		r.Err, r.Details, r.Fields = e, jerrno.DetailsOf(e), jerrno.FieldErrorsOf(e)
+		// 沿错误链找到带错误码的错误，信息与错误码保持一致
+		if ex := jerrno.CoderOf(e); ex != nil {
+			e, r.Code = ex, ex.ErrorCode()
 		}
-		if ex, ok := e.(ErrorWithHttpCode); ok && ex.ErrorHttpCode() != 0 {
-			httpCode = ex.ErrorHttpCode()
+		r.Msg = jerrno.Localize(e, RequestLocales(c)...)
+		if ex, ok := jerrno.HttpCodeOf(e); ok {
+			r.HttpCode = ex
 		}
 	}
-	c.JSON(httpCode, Response{code, data, msg})
+	c.Set(ctxKeyRespCode, r.Code)
+	EnvelopeOf(c).Render(c, r)
 }
 
 func Ok(c *gin.Context) {
@@ -0,0 +1,82 @@
+package jgin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// doRequest 用 handler 处理请求，返回响应及解析后的 Response
+func doRequest(t *testing.T, h gin.HandlerFunc, req *http.Request) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r := gin.New()
+	r.Any("/*path", h)
+	r.ServeHTTP(w, req)
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v, %s", err, w.Body.String())
+	}
+	return w, resp
+}
+
+func TestResultErr(t *testing.T) {
+	old, ok := jerrno.Messages.Lookup(400, "en")
+	t.Cleanup(func() {
+		if ok {
+			jerrno.Messages.Set("en", 400, old)
+		} else {
+			jerrno.Messages.Delete("en", 400)
+		}
+	})
+	jerrno.Messages.Set("en", 400, "Bad request")
+
+	w, resp := doRequest(t, func(c *gin.Context) {
+		ResultErr(nil, jerrno.WithHttpCode(jerrno.BadRequest, 422).WithFieldError("mobile", "手机号格式有误").WithDetail("form", "register"), c)
+	}, httptest.NewRequest(http.MethodGet, "/?lang=en", nil))
+	if w.Code != 422 || resp.Code != 400 || resp.Msg != "Bad request" {
+		t.Errorf("ResultErr() = %d %+v", w.Code, resp)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Field != "mobile" || resp.Details["form"] != "register" {
+		t.Errorf("ResultErr() 字段错误 = %+v", resp)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "zh-CN,en;q=0.9")
+	if _, resp = doRequest(t, func(c *gin.Context) { ResultErr(nil, jerrno.BadRequest, c) }, req); resp.Msg != "参数有误" {
+		t.Errorf("ResultErr(zh-CN,en) = %+v", resp)
+	}
+
+	w, resp = doRequest(t, func(c *gin.Context) {
+		ResultErr("data", nil, c)
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || resp.Code != SUCCESS || resp.Data != "data" {
+		t.Errorf("ResultErr(nil) = %d %+v", w.Code, resp)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	w, _ := doRequest(t, func(c *gin.Context) {
+		ResultErr(nil, jerrno.QueryNotFound.WithDetail("id", 1), c)
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+	_, err := DecodeResponse(w.Result(), nil)
+	if !errors.Is(err, jerrno.QueryNotFound) || jerrno.DetailsOf(err)["id"] != 1.0 {
+		t.Errorf("DecodeResponse() = %v", err)
+	}
+
+	w, _ = doRequest(t, func(c *gin.Context) {
+		OkWithData(map[string]int{"id": 2}, c)
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+	var data struct{ Id int }
+	if _, err = DecodeResponse(w.Result(), &data); err != nil || data.Id != 2 {
+		t.Errorf("DecodeResponse() = %v, %+v", err, data)
+	}
+}
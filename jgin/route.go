@@ -0,0 +1,98 @@
+package jgin
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 类型化的路由注册：用 Handle 处理请求，同时把请求、响应的类型登记到 DefaultOpenAPI 生成文档
+//
+//	api := r.Group("/api")
+//	jgin.GET(api, "/order/:id", svc.GetOrder, jgin.Summary("查询订单"), jgin.Tags("订单"))
+//	jgin.POST(api, "/order", svc.CreateOrder)
+//	r.GET("/openapi.json", jgin.OpenAPIHandler())
+
+// Router gin.Engine、gin.RouterGroup
+type Router interface {
+	gin.IRoutes
+	BasePath() string
+}
+
+// RouteDoc 接口的说明
+type RouteDoc struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+}
+
+// RouteOption 设置接口的说明
+type RouteOption func(d *RouteDoc)
+
+func Summary(s string) RouteOption {
+	return func(d *RouteDoc) { d.Summary = s }
+}
+
+func Description(s string) RouteOption {
+	return func(d *RouteDoc) { d.Description = s }
+}
+
+func Tags(tags ...string) RouteOption {
+	return func(d *RouteDoc) { d.Tags = append(d.Tags, tags...) }
+}
+
+func OperationID(id string) RouteOption {
+	return func(d *RouteDoc) { d.OperationID = id }
+}
+
+func Deprecated() RouteOption {
+	return func(d *RouteDoc) { d.Deprecated = true }
+}
+
+// Route 注册类型化的路由，见 Handle
+func Route[Req, Resp any](r Router, method, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	var doc RouteDoc
+	for _, opt := range opts {
+		opt(&doc)
+	}
+	fullPath := joinPath(r.BasePath(), relativePath)
+	DefaultOpenAPI.Add(method, fullPath, reflect.TypeOf((*Req)(nil)).Elem(), reflect.TypeOf((*Resp)(nil)).Elem(), doc)
+	return r.Handle(method, relativePath, Handle(fn))
+}
+
+func GET[Req, Resp any](r Router, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	return Route(r, http.MethodGet, relativePath, fn, opts...)
+}
+
+func POST[Req, Resp any](r Router, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	return Route(r, http.MethodPost, relativePath, fn, opts...)
+}
+
+func PUT[Req, Resp any](r Router, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	return Route(r, http.MethodPut, relativePath, fn, opts...)
+}
+
+func PATCH[Req, Resp any](r Router, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	return Route(r, http.MethodPatch, relativePath, fn, opts...)
+}
+
+func DELETE[Req, Resp any](r Router, relativePath string, fn func(ctx context.Context, req *Req) (Resp, error), opts ...RouteOption) gin.IRoutes {
+	return Route(r, http.MethodDelete, relativePath, fn, opts...)
+}
+
+// joinPath 与 gin 拼接路由组路径的方式相同
+func joinPath(base, relativePath string) string {
+	if relativePath == "" {
+		return base
+	}
+	p := path.Join(base, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && p[len(p)-1] != '/' {
+		return p + "/"
+	}
+	return p
+}
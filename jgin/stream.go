@@ -0,0 +1,289 @@
+package jgin
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xtulnx/jkit-go/jgorm"
+	"gorm.io/gorm"
+)
+
+// 流式导出大量数据：用 jgorm.FindInBatches4Ordered 分批查询，每批写完即刷新，客户端断开后停止查询。
+// 还没有输出时出错按 ResultErr 返回；已经开始输出后出错只能中断，错误记录到 c.Errors。
+// db 需要指定稳定的排序，否则分批的结果可能重复或遗漏。
+//
+//	r.GET("/order/export", func(c *gin.Context) {
+//		_ = jgin.StreamCSV[model.Order](c, db.Order("id"), jgin.StreamFilename("订单.csv"),
+//			jgin.StreamColumns(jgin.StreamColumn{Field: "id", Title: "编号"}, jgin.StreamColumn{Field: "price", Title: "金额"}))
+//	})
+
+const (
+	MIMENDJSON = "application/x-ndjson"
+	MIMECSV    = "text/csv"
+)
+
+// StreamColumn CSV 的一列
+type StreamColumn struct {
+	Field string // 字段的 json 名称或字段名
+	Title string // 表头，为空时使用 Field
+}
+
+type streamOptions struct {
+	batchSize int
+	columns   []StreamColumn
+	filename  string
+	bom       bool
+}
+
+type StreamOption func(o *streamOptions)
+
+// StreamBatchSize 每批查询的数量，默认 500
+func StreamBatchSize(n int) StreamOption {
+	return func(o *streamOptions) { o.batchSize = n }
+}
+
+// StreamColumns 指定 CSV 的列，默认为所有导出的字段，表头为 json 名称
+func StreamColumns(cols ...StreamColumn) StreamOption {
+	return func(o *streamOptions) { o.columns = cols }
+}
+
+// StreamFilename 作为附件下载的文件名
+func StreamFilename(name string) StreamOption {
+	return func(o *streamOptions) { o.filename = name }
+}
+
+// StreamBOM CSV 以 UTF-8 BOM 开头，便于 Excel 识别中文
+func StreamBOM() StreamOption {
+	return func(o *streamOptions) { o.bom = true }
+}
+
+// StreamNDJSON 按行输出 json，每行一条记录
+func StreamNDJSON[T any](c *gin.Context, db *gorm.DB, opts ...StreamOption) error {
+	o := newStreamOptions(opts)
+	var enc *json.Encoder
+	return stream(c, db, MIMENDJSON, o, func(w io.Writer) error {
+		enc = json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return nil
+	}, func(rows []T) error {
+		for i := range rows {
+			if err := enc.Encode(&rows[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// StreamCSV 输出 CSV，T 需要是结构体。实现了 encoding.TextMarshaler 的字段（如 jgormtypes.JDate、jtypes.JPrice）用 MarshalText 格式化
+func StreamCSV[T any](c *gin.Context, db *gorm.DB, opts ...StreamOption) error {
+	o := newStreamOptions(opts)
+	fields, titles, err := csvFields(reflect.TypeOf((*T)(nil)).Elem(), o.columns)
+	if err != nil {
+		ResultErr(nil, err, c)
+		return err
+	}
+	var cw *csv.Writer
+	record := make([]string, len(fields))
+	return stream(c, db, MIMECSV+"; charset=utf-8", o, func(w io.Writer) error {
+		if o.bom {
+			if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+				return err
+			}
+		}
+		cw = csv.NewWriter(w)
+		return cw.Write(titles)
+	}, func(rows []T) error {
+		for i := range rows {
+			rv := reflect.ValueOf(&rows[i]).Elem()
+			for j, index := range fields {
+				record[j] = ""
+				if fv, err := rv.FieldByIndexErr(index); err == nil {
+					record[j] = csvValue(fv)
+				}
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+func newStreamOptions(opts []StreamOption) streamOptions {
+	o := streamOptions{batchSize: 500}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = 500
+	}
+	return o
+}
+
+// stream 第一批数据（没有数据时为查询结束时）才输出响应头，begin 输出表头等
+func stream[T any](c *gin.Context, db *gorm.DB, contentType string, o streamOptions,
+	begin func(w io.Writer) error, write func(rows []T) error) error {
+	ctx := c.Request.Context()
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		c.Header("Content-Type", contentType)
+		if o.filename != "" {
+			c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": o.filename}))
+		}
+		c.Set(ctxKeyRespCode, SUCCESS)
+		c.Status(http.StatusOK)
+		return begin(c.Writer)
+	}
+
+	var rows []T
+	err := jgorm.FindInBatches4Ordered(db.WithContext(ctx), &rows, o.batchSize, func(tx *gorm.DB, batch int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := start(); err != nil {
+			return err
+		}
+		if err := write(rows); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}).Error
+	if err == nil {
+		if err = start(); err == nil {
+			c.Writer.Flush()
+		}
+	}
+	if err != nil {
+		if !started {
+			ResultErr(nil, err, c)
+		} else {
+			_ = c.Error(err)
+			c.Abort()
+		}
+	}
+	return err
+}
+
+// csvFields 列对应的字段，未指定列时为所有导出的字段，匿名嵌入的结构体展开
+func csvFields(t reflect.Type, cols []StreamColumn) (fields [][]int, titles []string, err error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("jgin: CSV 只支持结构体, 不支持 %s", t)
+	}
+	type tField struct {
+		name, goName string
+		index        []int
+	}
+	var all []tField
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			idx := append(append([]int{}, index...), i)
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+				walk(ft, idx)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			all = append(all, tField{name, f.Name, idx})
+		}
+	}
+	walk(t, nil)
+
+	if len(cols) == 0 {
+		for _, f := range all {
+			fields, titles = append(fields, f.index), append(titles, f.name)
+		}
+		return fields, titles, nil
+	}
+	for _, col := range cols {
+		i := slices.IndexFunc(all, func(f tField) bool { return f.name == col.Field || f.goName == col.Field })
+		if i < 0 {
+			return nil, nil, fmt.Errorf("jgin: %s 没有字段 %s", t, col.Field)
+		}
+		title := col.Title
+		if title == "" {
+			title = col.Field
+		}
+		fields, titles = append(fields, all[i].index), append(titles, title)
+	}
+	return fields, titles, nil
+}
+
+// csvValue 格式化字段：TextMarshaler > driver.Valuer > 基本类型 > json
+func csvValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			if b, err := m.MarshalText(); err == nil {
+				return string(b)
+			}
+		}
+	}
+	switch x := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		if b, err := x.MarshalText(); err == nil {
+			return string(b)
+		}
+	case driver.Valuer:
+		if dv, err := x.Value(); err == nil {
+			if dv == nil {
+				return ""
+			}
+			return csvValue(reflect.ValueOf(dv))
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes())
+		}
+	}
+	b, _ := json.Marshal(v.Interface())
+	return string(b)
+}
@@ -0,0 +1,151 @@
+package jgin
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/xtulnx/jkit-go/jgormtypes"
+	"github.com/xtulnx/jkit-go/jtypes"
+	"gorm.io/gorm"
+)
+
+type tStreamItem struct {
+	ID     uint             `gorm:"primarykey" json:"id"`
+	Name   string           `json:"name"`
+	Price  jtypes.JPrice    `json:"price"`
+	Day    jgormtypes.JDate `json:"day"`
+	Note   *string          `json:"note"`
+	Secret string           `json:"-"`
+}
+
+func openStreamDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:stream?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&tStreamItem{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Where("1 = 1").Delete(&tStreamItem{})
+	note := "备注,有逗号"
+	for i := 1; i <= 7; i++ {
+		item := tStreamItem{ID: uint(i), Name: "n" + string(rune('0'+i)), Price: jtypes.NewPrice(float64(i) + 0.5),
+			Day: jgormtypes.NewDate(2024, 1, i), Secret: "x"}
+		if i == 1 {
+			item.Note = &note
+		}
+		db.Create(&item)
+	}
+	return db
+}
+
+func TestStreamCSV(t *testing.T) {
+	db := openStreamDB(t)
+	r := gin.New()
+	r.GET("/all", func(c *gin.Context) {
+		_ = StreamCSV[tStreamItem](c, db.Order("id"), StreamBatchSize(3), StreamFilename("订单.csv"))
+	})
+	r.GET("/cols", func(c *gin.Context) {
+		_ = StreamCSV[tStreamItem](c, db.Where("id > ?", 5).Order("id desc"), StreamBOM(),
+			StreamColumns(StreamColumn{Field: "ID", Title: "编号"}, StreamColumn{Field: "price"}))
+	})
+	r.GET("/bad", func(c *gin.Context) {
+		_ = StreamCSV[tStreamItem](c, db, StreamColumns(StreamColumn{Field: "secret"}))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/all", nil))
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, _ := jtypes.NewPrice(1.5).MarshalText()
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), MIMECSV) || !strings.Contains(w.Header().Get("Content-Disposition"), "attachment") ||
+		len(records) != 8 || !reflect.DeepEqual(records[0], []string{"id", "name", "price", "day", "note"}) ||
+		!reflect.DeepEqual(records[1], []string{"1", "n1", string(price), "2024-01-01", "备注,有逗号"}) || records[2][4] != "" {
+		t.Errorf("StreamCSV() = %s %q", w.Header(), records)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cols", nil))
+	if body := w.Body.String(); !strings.HasPrefix(body, "\xEF\xBB\xBF编号,price\n7,") || strings.Count(body, "\n") != 3 {
+		t.Errorf("StreamCSV(列) = %q", body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bad", nil))
+	var resp Response
+	if err = json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Code == SUCCESS {
+		t.Errorf("StreamCSV(字段不存在) = %s", w.Body.String())
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	db := openStreamDB(t)
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		_ = StreamNDJSON[tStreamItem](c, db.Order("id"), StreamBatchSize(2))
+	})
+	r.GET("/empty", func(c *gin.Context) {
+		_ = StreamNDJSON[tStreamItem](c, db.Where("id < 0"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	var ids []uint
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var item tStreamItem
+		if err := json.Unmarshal(sc.Bytes(), &item); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, item.ID)
+	}
+	if w.Header().Get("Content-Type") != MIMENDJSON || !reflect.DeepEqual(ids, []uint{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("StreamNDJSON() = %s %v", w.Header(), ids)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/empty", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != MIMENDJSON || w.Body.Len() != 0 {
+		t.Errorf("StreamNDJSON(空) = %d %s %q", w.Code, w.Header(), w.Body.String())
+	}
+}
+
+// tCancelWriter 第一次刷新后断开
+type tCancelWriter struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (w tCancelWriter) Flush() {
+	w.ResponseRecorder.Flush()
+	w.cancel()
+}
+
+func TestStreamCancel(t *testing.T) {
+	db := openStreamDB(t)
+	var err error
+	r := gin.New()
+	r.GET("/", func(c *gin.Context) {
+		err = StreamNDJSON[tStreamItem](c, db.Order("id"), StreamBatchSize(2))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := tCancelWriter{httptest.NewRecorder(), cancel}
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if !errors.Is(err, context.Canceled) || strings.Count(w.Body.String(), "\n") != 2 {
+		t.Errorf("StreamNDJSON(断开) = %v %q", err, w.Body.String())
+	}
+}
@@ -0,0 +1,225 @@
+package jgin
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+// 参数校验失败时，把 validator 的错误转成 jerrno.BadRequest，按请求的语言给出每个字段的信息，
+// 字段名使用 json 标签，没有时使用 form 标签，如：
+//
+//	{"code": 400, "msg": "参数有误", "fields": [{"field": "mobile", "msg": "不能为空"}]}
+//
+// 信息按校验规则登记，{param} 为规则的参数，如 min=2 中的 2；
+// 字符串、数组等按长度校验的规则，先查找 规则.len，如 min.len
+
+// DefaultValidationLocale 请求的语言都没有对应信息时使用，默认同 jerrno.DefaultLocale
+var DefaultValidationLocale = jerrno.DefaultLocale
+
+var validationMessages = struct {
+	sync.RWMutex
+	msgs map[string]map[string]string // locale -> tag -> msg
+}{msgs: map[string]map[string]string{
+	"zh": {
+		"required": "不能为空",
+		"min":      "不能小于 {param}",
+		"min.len":  "长度不能小于 {param}",
+		"max":      "不能大于 {param}",
+		"max.len":  "长度不能大于 {param}",
+		"len":      "必须等于 {param}",
+		"len.len":  "长度必须为 {param}",
+		"eq":       "必须等于 {param}",
+		"ne":       "不能等于 {param}",
+		"gt":       "必须大于 {param}",
+		"gt.len":   "长度必须大于 {param}",
+		"gte":      "不能小于 {param}",
+		"gte.len":  "长度不能小于 {param}",
+		"lt":       "必须小于 {param}",
+		"lt.len":   "长度必须小于 {param}",
+		"lte":      "不能大于 {param}",
+		"lte.len":  "长度不能大于 {param}",
+		"oneof":    "必须是 [{param}] 中的一个",
+		"eqfield":  "必须与 {param} 相同",
+		"nefield":  "不能与 {param} 相同",
+		"unique":   "不能有重复",
+		"email":    "邮箱格式有误",
+		"url":      "网址格式有误",
+		"uuid":     "UUID 格式有误",
+		"ip":       "IP 地址格式有误",
+		"numeric":  "必须是数字",
+		"number":   "必须是数字",
+		"alpha":    "只能包含字母",
+		"alphanum": "只能包含字母和数字",
+		"datetime": "时间格式有误，应为 {param}",
+		"e164":     "手机号格式有误",
+		"":         "校验失败（{tag}）",
+	},
+	"en": {
+		"required": "is required",
+		"min":      "must be at least {param}",
+		"min.len":  "length must be at least {param}",
+		"max":      "must be at most {param}",
+		"max.len":  "length must be at most {param}",
+		"len":      "must be {param}",
+		"len.len":  "length must be {param}",
+		"eq":       "must be {param}",
+		"ne":       "must not be {param}",
+		"gt":       "must be greater than {param}",
+		"gt.len":   "length must be greater than {param}",
+		"gte":      "must be at least {param}",
+		"gte.len":  "length must be at least {param}",
+		"lt":       "must be less than {param}",
+		"lt.len":   "length must be less than {param}",
+		"lte":      "must be at most {param}",
+		"lte.len":  "length must be at most {param}",
+		"oneof":    "must be one of [{param}]",
+		"eqfield":  "must be equal to {param}",
+		"nefield":  "must not be equal to {param}",
+		"unique":   "must not contain duplicates",
+		"email":    "must be a valid email address",
+		"url":      "must be a valid URL",
+		"uuid":     "must be a valid UUID",
+		"ip":       "must be a valid IP address",
+		"numeric":  "must be numeric",
+		"number":   "must be a number",
+		"alpha":    "must contain only letters",
+		"alphanum": "must contain only letters and numbers",
+		"datetime": "must be a time in the format {param}",
+		"e164":     "must be a valid phone number",
+		"":         "failed on the '{tag}' rule",
+	},
+}}
+
+// RegValidationMessage 登记校验规则的信息，tag 为空时是没有登记的规则的默认信息，{tag} 为规则名
+//
+//	jgin.RegValidationMessage("zh", "mobile", "手机号格式有误")
+func RegValidationMessage(locale, tag, msg string) {
+	locale = jerrno.NormalizeLocale(locale)
+	validationMessages.Lock()
+	defer validationMessages.Unlock()
+	m, ok := validationMessages.msgs[locale]
+	if !ok {
+		m = make(map[string]string)
+		validationMessages.msgs[locale] = m
+	}
+	m[tag] = msg
+}
+
+// ValidationError 把校验错误转成 jerrno.BadRequest，带上每个字段的信息；不是校验错误时原样返回。
+// obj 为绑定的对象，用于找到字段的 json、form 名称
+func ValidationError(err error, obj any, locales ...string) error {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return err
+	}
+	t := reflect.TypeOf(obj)
+	ex := jerrno.BadRequest.WithError(err)
+	for _, fe := range ve {
+		ex = ex.WithFieldError(fieldPath(t, fe.StructNamespace()), validationMessage(fe, locales))
+	}
+	return ex
+}
+
+// validationMessage 按语言的优先顺序查找，每个语言先精确匹配，再匹配主语言，如 en-us => en
+func validationMessage(fe validator.FieldError, locales []string) string {
+	tag := fe.Tag()
+	keys := []string{tag}
+	switch fe.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		keys = []string{tag + ".len", tag}
+	}
+	if strings.HasPrefix(tag, "required") {
+		keys = append(keys, "required")
+	}
+	keys = append(keys, "")
+
+	validationMessages.RLock()
+	defer validationMessages.RUnlock()
+	lookup := func(locale string) (string, bool) {
+		m, ok := validationMessages.msgs[locale]
+		if !ok {
+			return "", false
+		}
+		for _, k := range keys {
+			if msg, ok := m[k]; ok {
+				return msg, true
+			}
+		}
+		return "", false
+	}
+	for _, l := range append(slices.Clip(locales), DefaultValidationLocale) {
+		l = jerrno.NormalizeLocale(l)
+		msg, ok := lookup(l)
+		if !ok {
+			if i := strings.IndexByte(l, '-'); i > 0 {
+				msg, ok = lookup(l[:i])
+			}
+		}
+		if ok {
+			return jerrno.Render(msg, map[string]any{"param": fe.Param(), "tag": tag})
+		}
+	}
+	return fe.Error()
+}
+
+// fieldPath 按结构体路径找到各级字段的 json、form 名称，没有标签的内嵌结构体不计入，如
+//
+//	Req.Addr.City => addr.city，Req.Items[0].Qty => items[0].qty，Req.PageReq.Page => page
+func fieldPath(t reflect.Type, ns string) string {
+	segs := strings.Split(ns, ".")
+	if len(segs) > 1 {
+		segs = segs[1:]
+	}
+	var names []string
+	for _, seg := range segs {
+		name, index, _ := strings.Cut(seg, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		t = derefType(t)
+		if t == nil || t.Kind() != reflect.Struct {
+			names = append(names, name+index)
+			t = nil
+			continue
+		}
+		sf, ok := t.FieldByName(name)
+		if !ok {
+			names = append(names, name+index)
+			t = nil
+			continue
+		}
+		tagName := fieldTagName(sf)
+		if !sf.Anonymous || tagName != sf.Name {
+			names = append(names, tagName+index)
+		}
+		t = sf.Type
+		for i := strings.Count(index, "["); i > 0; i-- {
+			if t = derefType(t); t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+func fieldTagName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(sf.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
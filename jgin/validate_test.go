@@ -0,0 +1,76 @@
+package jgin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+)
+
+type tValidateItem struct {
+	Qty int `json:"qty" binding:"gt=0"`
+}
+
+type tValidateEmbed struct {
+	Code string `json:"code" binding:"len=4"`
+}
+
+type tValidateReq struct {
+	tValidateEmbed
+	Mobile string `json:"mobile" binding:"required"`
+	Name   string `form:"name" binding:"min=2"`
+	Age    int    `json:"age" binding:"gte=18"`
+	Addr   struct {
+		City string `json:"city" binding:"required"`
+	} `json:"addr"`
+	Items []tValidateItem `json:"items" binding:"dive"`
+	Tag   string          `json:"tag" binding:"hexcolor"`
+}
+
+func TestValidationError(t *testing.T) {
+	h := Handle(func(ctx context.Context, req *tValidateReq) (any, error) { return nil, nil })
+	body := `{"code":"1","name":"a","age":3,"items":[{"qty":1},{"qty":0}],"tag":"x"}`
+
+	for _, v := range []struct {
+		lang string
+		want []jerrno.FieldError
+	}{
+		{"", []jerrno.FieldError{
+			{Field: "code", Msg: "长度必须为 4"},
+			{Field: "mobile", Msg: "不能为空"},
+			{Field: "name", Msg: "长度不能小于 2"},
+			{Field: "age", Msg: "不能小于 18"},
+			{Field: "addr.city", Msg: "不能为空"},
+			{Field: "items[1].qty", Msg: "必须大于 0"},
+			{Field: "tag", Msg: "校验失败（hexcolor）"},
+		}},
+		{"fr, en-US;q=0.8", []jerrno.FieldError{
+			{Field: "code", Msg: "length must be 4"},
+			{Field: "mobile", Msg: "is required"},
+			{Field: "name", Msg: "length must be at least 2"},
+			{Field: "age", Msg: "must be at least 18"},
+			{Field: "addr.city", Msg: "is required"},
+			{Field: "items[1].qty", Msg: "must be greater than 0"},
+			{Field: "tag", Msg: "failed on the 'hexcolor' rule"},
+		}},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", v.lang)
+		_, resp := doRequest(t, h, req)
+		if resp.Code != 400 || !reflect.DeepEqual(resp.Fields, v.want) {
+			t.Errorf("校验失败(%q) = %+v", v.lang, resp)
+		}
+	}
+
+	RegValidationMessage("zh-CN", "hexcolor", "颜色格式有误")
+	req := httptest.NewRequest(http.MethodGet, "/?lang=zh_CN&name=ab", nil)
+	_, resp := doRequest(t, h, req)
+	if len(resp.Fields) != 5 || resp.Fields[4] != (jerrno.FieldError{Field: "tag", Msg: "颜色格式有误"}) {
+		t.Errorf("自定义信息 = %+v", resp)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 	_ "unsafe"
 
+	"github.com/xtulnx/jkit-go/jlog"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/utils"
@@ -75,6 +76,23 @@ type tLogger struct {
 	traceStr, traceErrStr, traceWarnStr string
 }
 
+// CtxWriter 可以获取 context 的 Writer，用于在日志中加入请求 ID 等信息，见 debug_zap
+type CtxWriter interface {
+	PrintfCtx(ctx context.Context, format string, args ...interface{})
+}
+
+// printf Writer 实现了 CtxWriter 时交给它处理，否则在行首加上请求 ID
+func (l tLogger) printf(ctx context.Context, format string, args ...interface{}) {
+	if w, ok := l.Writer.(CtxWriter); ok {
+		w.PrintfCtx(ctx, format, args...)
+		return
+	}
+	if id := jlog.RequestID(ctx); id != "" {
+		format = "[" + strings.ReplaceAll(id, "%", "%%") + "] " + format
+	}
+	l.Printf(format, args...)
+}
+
 // LogMode log mode
 func (l *tLogger) LogMode(level logger.LogLevel) logger.Interface {
 	newlogger := *l
@@ -85,21 +103,21 @@ func (l *tLogger) LogMode(level logger.LogLevel) logger.Interface {
 // Info print info
 func (l tLogger) Info(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Info {
-		l.Printf(l.infoStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
+		l.printf(ctx, l.infoStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
 	}
 }
 
 // Warn print warn messages
 func (l tLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Warn {
-		l.Printf(l.warnStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
+		l.printf(ctx, l.warnStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
 	}
 }
 
 // Error print error messages
 func (l tLogger) Error(ctx context.Context, msg string, data ...interface{}) {
 	if l.LogLevel >= logger.Error {
-		l.Printf(l.errStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
+		l.printf(ctx, l.errStr+msg, append([]interface{}{FileWithLineNum()}, data...)...)
 	}
 }
 
@@ -114,24 +132,24 @@ func (l tLogger) Trace(ctx context.Context, begin time.Time, fc func() (string,
 	case err != nil && l.LogLevel >= logger.Error && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.IgnoreRecordNotFoundError):
 		sql, rows := fc()
 		if rows == -1 {
-			l.Printf(l.traceErrStr, FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.printf(ctx, l.traceErrStr, FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceErrStr, FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.printf(ctx, l.traceErrStr, FileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	case elapsed > l.SlowThreshold && l.SlowThreshold != 0 && l.LogLevel >= logger.Warn:
 		sql, rows := fc()
 		slowLog := fmt.Sprintf("SLOW SQL >= %v", l.SlowThreshold)
 		if rows == -1 {
-			l.Printf(l.traceWarnStr, FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.printf(ctx, l.traceWarnStr, FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceWarnStr, FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.printf(ctx, l.traceWarnStr, FileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	case l.LogLevel == logger.Info:
 		sql, rows := fc()
 		if rows == -1 {
-			l.Printf(l.traceStr, FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
+			l.printf(ctx, l.traceStr, FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
 		} else {
-			l.Printf(l.traceStr, FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+			l.printf(ctx, l.traceStr, FileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
 		}
 	}
 }
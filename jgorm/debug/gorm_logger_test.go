@@ -0,0 +1,31 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xtulnx/jkit-go/jlog"
+	"gorm.io/gorm/logger"
+)
+
+type tWriter struct {
+	lines []string
+}
+
+func (w *tWriter) Printf(format string, args ...interface{}) {
+	w.lines = append(w.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLoggerRequestID(t *testing.T) {
+	w := &tWriter{}
+	l := NewLogger(w, logger.Config{LogLevel: logger.Info})
+	ctx := jlog.WithRequestID(context.Background(), "req%1")
+	l.Info(ctx, "hello %d", 1)
+	l.Info(context.Background(), "hello %d", 2)
+	if len(w.lines) != 2 || !strings.HasPrefix(w.lines[0], "[req%1] ") || !strings.HasSuffix(w.lines[0], "hello 1") ||
+		strings.HasPrefix(w.lines[1], "[") {
+		t.Errorf("lines = %q", w.lines)
+	}
+}
@@ -1,6 +1,7 @@
 package debug_zap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/xtulnx/jkit-go/jgorm/builder"
 	"github.com/xtulnx/jkit-go/jgorm/config"
 	"github.com/xtulnx/jkit-go/jgorm/debug"
+	"github.com/xtulnx/jkit-go/jlog"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gorm.io/gorm/logger"
@@ -38,6 +40,11 @@ type tLogger struct {
 
 // Printf 日志打印
 func (t tLogger) Printf(s string, i ...interface{}) {
+	t.PrintfCtx(context.Background(), s, i...)
+}
+
+// PrintfCtx 日志打印，带上 context 中的请求 ID，见 jlog.CtxFields
+func (t tLogger) PrintfCtx(ctx context.Context, s string, i ...interface{}) {
 	if t.logger == nil {
 		return
 	}
@@ -50,7 +57,7 @@ func (t tLogger) Printf(s string, i ...interface{}) {
 	} else {
 		s1 = s
 	}
-	t.logger.Log(t.level, s1, zap.Skip())
+	t.logger.Log(t.level, s1, jlog.CtxFields(ctx)...)
 }
 
 // 创建一个调试日志
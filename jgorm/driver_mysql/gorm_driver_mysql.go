@@ -1,6 +1,10 @@
 package driver_mysql
 
 import (
+	"errors"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/xtulnx/jkit-go/jerrno"
 	"github.com/xtulnx/jkit-go/jgorm/builder"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -11,4 +15,14 @@ func init() {
 		Register("mysql", func(dsn string) gorm.Dialector {
 			return mysql.Open(dsn) // 需要 import _ "gorm.io/driver/mysql"
 		})
+	jerrno.RegTranslator(TranslateError)
+}
+
+// TranslateError 重复键 => jerrno.Conflict
+func TranslateError(err error) jerrno.ErrEx {
+	var e1 *mysqlDriver.MySQLError
+	if errors.As(err, &e1) && e1.Number == 1062 { // ER_DUP_ENTRY
+		return jerrno.Conflict
+	}
+	return nil
 }
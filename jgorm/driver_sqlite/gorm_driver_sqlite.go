@@ -1,10 +1,15 @@
 package driver_sqlite
 
 import (
+	"errors"
+
+	"github.com/xtulnx/jkit-go/jerrno"
 	"github.com/xtulnx/jkit-go/jgorm/builder"
 	//"gorm.io/driver/sqlite"
+	gosqlite "github.com/glebarez/go-sqlite"
 	"github.com/glebarez/sqlite" // 纯 golang 实现
 	"gorm.io/gorm"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 func init() {
@@ -12,4 +17,17 @@ func init() {
 		Register("sqlite", func(dsn string) gorm.Dialector {
 			return sqlite.Open(dsn)
 		})
+	jerrno.RegTranslator(TranslateError)
+}
+
+// TranslateError 唯一键、主键冲突 => jerrno.Conflict
+func TranslateError(err error) jerrno.ErrEx {
+	var e1 *gosqlite.Error
+	if errors.As(err, &e1) {
+		switch e1.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return jerrno.Conflict
+		}
+	}
+	return nil
 }
@@ -0,0 +1,146 @@
+package jgorm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 游标分页（keyset）：按排序列的值定位，替代 offset，翻页的速度与页号无关。
+// 排序列的组合需要唯一，通常以主键结尾，且列值不能为 NULL。
+//
+//	cols := []jgorm.KeysetColumn{{Column: q.Order.CreatedAt, Desc: true}, {Column: q.Order.ID, Desc: true}}
+//	db, err := jgorm.KeysetDB(db, cols, lastValues, false)
+
+var errKeysetNoColumns = errors.New("jgorm: 游标分页需要指定排序列")
+
+// KeysetColumn 排序列
+//
+//	Column: field.Expr, string 列名, clause.Column
+type KeysetColumn struct {
+	Column any
+	Desc   bool
+}
+
+// column 对应的列，field.Expr 不是列时使用其列名
+func (k KeysetColumn) column() clause.Column {
+	switch v := k.Column.(type) {
+	case field.Expr:
+		if c, ok := v.RawExpr().(clause.Column); ok {
+			return c
+		}
+		return clause.Column{Name: string(v.ColumnName())}
+	case string:
+		return clause.Column{Name: v}
+	case clause.Column:
+		return v
+	}
+	panic(fmt.Sprintf("unsupported keyset column %T", k.Column))
+}
+
+// Name 列名，不带表名
+func (k KeysetColumn) Name() string {
+	return k.column().Name
+}
+
+// KeysetCond 位于 values 之后（backward 时为之前）的条件，如 a 倒序、b 正序：
+//
+//	(a < ? OR (a = ? AND b > ?))
+func KeysetCond(cols []KeysetColumn, values []any, backward bool) (clause.Expression, error) {
+	if len(cols) == 0 {
+		return nil, errKeysetNoColumns
+	}
+	if len(values) != len(cols) {
+		return nil, fmt.Errorf("jgorm: 游标有 %d 个值, 排序列有 %d 个", len(values), len(cols))
+	}
+	var ors []string
+	var vars []any
+	for i, c := range cols {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, "? = ?")
+			vars = append(vars, cols[j].column(), values[j])
+		}
+		if c.Desc != backward {
+			ands = append(ands, "? < ?")
+		} else {
+			ands = append(ands, "? > ?")
+		}
+		vars = append(vars, c.column(), values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(ors, " OR ") + ")", Vars: vars}, nil
+}
+
+// KeysetOrder 排序，backward 时反向
+func KeysetOrder(cols []KeysetColumn, backward bool) clause.OrderBy {
+	orderBy := clause.OrderBy{Columns: make([]clause.OrderByColumn, len(cols))}
+	for i, c := range cols {
+		orderBy.Columns[i] = clause.OrderByColumn{Column: c.column(), Desc: c.Desc != backward}
+	}
+	return orderBy
+}
+
+// KeysetDB 增加游标条件与排序，values 为空时从头开始。
+// backward 时向前翻页，values 为当前页第一行的值，查询结果的顺序是反的
+func KeysetDB(db *gorm.DB, cols []KeysetColumn, values []any, backward bool) (*gorm.DB, error) {
+	if len(cols) == 0 {
+		return nil, errKeysetNoColumns
+	}
+	if len(values) > 0 {
+		cond, err := KeysetCond(cols, values, backward)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Clauses(cond)
+	}
+	return db.Clauses(KeysetOrder(cols, backward)), nil
+}
+
+// KeysetDao 用于 gen 的 dao，见 KeysetDB
+func KeysetDao(dao gen.Dao, cols []KeysetColumn, values []any, backward bool) error {
+	if len(cols) == 0 {
+		return errKeysetNoColumns
+	}
+	if len(values) > 0 {
+		cond, err := KeysetCond(cols, values, backward)
+		if err != nil {
+			return err
+		}
+		DbClause(dao, cond)
+	}
+	DbClause(dao, KeysetOrder(cols, backward))
+	return nil
+}
+
+// KeysetFields 排序列对应的模型字段，用于从记录中取值、还原游标中的值
+func KeysetFields(db *gorm.DB, model any, cols []KeysetColumn) ([]*schema.Field, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	fields := make([]*schema.Field, len(cols))
+	for i, c := range cols {
+		if fields[i] = stmt.Schema.LookUpField(c.Name()); fields[i] == nil {
+			return nil, fmt.Errorf("jgorm: %s 没有排序列 %s", stmt.Schema.Name, c.Name())
+		}
+	}
+	return fields, nil
+}
+
+// KeysetValues 记录的排序值，即下一页（或上一页）的 values
+func KeysetValues(db *gorm.DB, fields []*schema.Field, row any) []any {
+	rv := reflect.Indirect(reflect.ValueOf(row))
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		values[i], _ = f.ValueOf(db.Statement.Context, rv)
+	}
+	return values
+}
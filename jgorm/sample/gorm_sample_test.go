@@ -2,8 +2,12 @@ package sample
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/xtulnx/jkit-go/jerrno"
+	_ "github.com/xtulnx/jkit-go/jerrno/translator"
 	"github.com/xtulnx/jkit-go/jgorm"
 	"github.com/xtulnx/jkit-go/jgorm/config"
 	"gorm.io/datatypes"
@@ -85,3 +89,25 @@ func TestDaoWrapper(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateError(t *testing.T) {
+	db0, err := OpenDb(config.NewDbProvider1("sqlite", "file:translate?mode=memory&cache=shared"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db0.AutoMigrate(&Foo1{}); err != nil {
+		t.Fatal(err)
+	}
+	day := sql.NullTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), Valid: true}
+	if err = db0.Create(&Foo1{StoreId: 1, BusinessDay: day}).Error; err != nil {
+		t.Fatal(err)
+	}
+	err = db0.Create(&Foo1{StoreId: 1, BusinessDay: day}).Error
+	if err1 := jerrno.Translate(err); !errors.Is(err1, jerrno.Conflict) {
+		t.Errorf("重复键 %v => %v", err, err1)
+	}
+	err = db0.First(&Foo1{}, "store_id = ?", 2).Error
+	if err1 := jerrno.Translate(err); !errors.Is(err1, jerrno.QueryNotFound) {
+		t.Errorf("记录不存在 %v => %v", err, err1)
+	}
+}
@@ -0,0 +1,75 @@
+package jlog
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// 请求 ID、链路 ID 保存在 context 中，日志通过 ForContext 自动带上
+
+const (
+	KeyRequestID = "requestId"
+	KeyTraceID   = "traceId"
+)
+
+type ctxKey int
+
+const (
+	ctxKeyRequestID ctxKey = iota
+	ctxKeyTraceID
+)
+
+// WithRequestID 在 context 中保存请求 ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestID, id)
+}
+
+// RequestID context 中的请求 ID，没有时为空
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKeyRequestID).(string)
+	return id
+}
+
+// WithTraceID 在 context 中保存链路 ID，如 W3C traceparent 的 trace-id
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyTraceID, id)
+}
+
+// TraceID context 中的链路 ID，没有时为空
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxKeyTraceID).(string)
+	return id
+}
+
+// CtxFields context 中的请求 ID、链路 ID
+func CtxFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String(KeyRequestID, id))
+	}
+	if id := TraceID(ctx); id != "" {
+		fields = append(fields, zap.String(KeyTraceID, id))
+	}
+	return fields
+}
+
+// ForContext 带上 context 中请求 ID、链路 ID 的日志器
+//
+//	jlog.ForContext(ctx, logger).Info("创建订单", zap.Uint("id", id))
+func ForContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	if fields := CtxFields(ctx); len(fields) > 0 {
+		return l.With(fields...)
+	}
+	return l
+}
+
+func (L *Logger) ForContext(ctx context.Context) *Logger {
+	return (*Logger)(ForContext(ctx, (*zap.Logger)(L)))
+}
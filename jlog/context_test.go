@@ -0,0 +1,27 @@
+package jlog
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestForContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(core)
+
+	ctx := WithTraceID(WithRequestID(context.Background(), "req-1"), "4bf92f3577b34da6a3ce929d0e0e4736")
+	ForContext(ctx, l).Info("a")
+	ForContext(context.Background(), l).Info("b")
+
+	entries := logs.AllUntimed()
+	if m := entries[0].ContextMap(); m[KeyRequestID] != "req-1" || m[KeyTraceID] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("ForContext() = %v", m)
+	}
+	if m := entries[1].ContextMap(); len(m) != 0 {
+		t.Errorf("ForContext(空) = %v", m)
+	}
+}
@@ -0,0 +1,83 @@
+package jlog
+
+import (
+	"errors"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// jerrno 错误的日志字段，以及按严重程度记录日志
+
+const (
+	KeyErrorCode      = "errorCode"
+	KeyErrorCause     = "errorCause"
+	KeyErrorRetryable = "retryable"
+	KeyErrorStack     = "errorStack"
+)
+
+// ErrStack jerrno 错误创建时的调用栈，需要 jerrno.EnableStack 开启，没有调用栈时忽略
+func ErrStack(err error) zap.Field {
+	if s := jerrno.StackOf(err); len(s) > 0 {
+		return zap.String(KeyErrorStack, s.String())
+	}
+	return zap.Skip()
+}
+
+// ErrFields 错误信息、错误码、最内层的原始错误、调用栈。
+// 不用 zap.Error，避免 jerrno 错误的 %+v 输出（errorVerbose）与调用栈重复
+func ErrFields(err error) []zap.Field {
+	if err == nil {
+		return nil
+	}
+	fields := []zap.Field{zap.String("error", err.Error())}
+	if code, ok := jerrno.CodeOf(err); ok {
+		fields = append(fields, zap.Int(KeyErrorCode, code))
+	}
+	if cause := rootCause(err); cause != err {
+		fields = append(fields, zap.String(KeyErrorCause, cause.Error()))
+	}
+	if s := jerrno.StackOf(err); len(s) > 0 {
+		fields = append(fields, zap.String(KeyErrorStack, s.String()))
+	}
+	return fields
+}
+
+func rootCause(err error) error {
+	for {
+		e1 := errors.Unwrap(err)
+		if e1 == nil {
+			return err
+		}
+		err = e1
+	}
+}
+
+// ErrLevel 按错误的严重程度确定日志级别，见 jerrno.SeverityOf
+func ErrLevel(err error) zapcore.Level {
+	switch jerrno.SeverityOf(err) {
+	case jerrno.SeverityDebug:
+		return zapcore.DebugLevel
+	case jerrno.SeverityInfo:
+		return zapcore.InfoLevel
+	case jerrno.SeverityWarn:
+		return zapcore.WarnLevel
+	}
+	return zapcore.ErrorLevel
+}
+
+// LogErr 按错误的严重程度记录日志，附带错误码、原始错误、是否可以重试
+func LogErr(l *zap.Logger, msg string, err error, fields ...zap.Field) {
+	if l == nil || err == nil {
+		return
+	}
+	level := ErrLevel(err)
+	if ce := l.Check(level, msg); ce != nil {
+		fields = append(fields, ErrFields(err)...)
+		if jerrno.RetryableOf(err) {
+			fields = append(fields, zap.Bool(KeyErrorRetryable, true))
+		}
+		ce.Write(fields...)
+	}
+}
@@ -0,0 +1,39 @@
+package jlog
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/xtulnx/jkit-go/jerrno"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogErr(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(core)
+
+	LogErr(l, "a", jerrno.BadRequest.WithError(io.EOF))
+	LogErr(l, "b", fmt.Errorf("x: %w", jerrno.InternalServerError))
+	LogErr(l, "c", jerrno.TooManyRequests)
+	LogErr(l, "d", nil)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("日志数量 %d", len(entries))
+	}
+	for i, want := range []zapcore.Level{zapcore.InfoLevel, zapcore.ErrorLevel, zapcore.WarnLevel} {
+		if entries[i].Level != want {
+			t.Errorf("[%s] 级别 %v, want %v", entries[i].Message, entries[i].Level, want)
+		}
+	}
+	m := entries[0].ContextMap()
+	if m[KeyErrorCode] != int64(400) || m[KeyErrorCause] != "EOF" {
+		t.Errorf("字段 %v", m)
+	}
+	if entries[2].ContextMap()[KeyErrorRetryable] != true {
+		t.Errorf("字段 %v", entries[2].ContextMap())
+	}
+}